@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/kuchuk-borom-debbarma/GitGrove/src/internal/grove/affected"
+	"github.com/kuchuk-borom-debbarma/GitGrove/src/internal/grove/run"
+	"github.com/spf13/cobra"
+)
+
+func newAffectedCommand() *cobra.Command {
+	var paths bool
+	cmd := describe(&cobra.Command{
+		Use: "affected (<base> <head> | <base>..<head>)",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 1 || len(args) > 2 {
+				return usageErrorf(cmd, "%s expects a base and a head revision (usage: %s)", cmd.CommandPath(), cmd.UseLine())
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			base, head, err := revisionArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+			result, err := affected.Affected(gitClient, workDir, base, head)
+			if err != nil {
+				return fmt.Errorf("failed to compute affected repositories: %w", err)
+			}
+			render(result, func() { printAffected(result, paths) })
+			return nil
+		},
+	}, affected.Description())
+	cmd.Flags().BoolVar(&paths, "paths", false, "print repository paths instead of names")
+	return cmd
+}
+
+// revisionArgs accepts "<base> <head>" as well as "<base>..<head>".
+func revisionArgs(cmd *cobra.Command, args []string) (string, string, error) {
+	if len(args) == 2 {
+		return args[0], args[1], nil
+	}
+	base, head, err := affected.ParseRange(args[0])
+	if err != nil {
+		return "", "", usageErrorf(cmd, "%v", err)
+	}
+	return base, head, nil
+}
+
+// printAffected prints one repository per line on stdout, so the output can feed a shell loop;
+// shared path and root changes are reported on stderr.
+func printAffected(result *affected.Result, paths bool) {
+	for _, repo := range result.Repos {
+		if paths {
+			fmt.Println(repo.Path)
+		} else {
+			fmt.Println(repo.Name)
+		}
+	}
+	for _, shared := range result.Shared {
+		fmt.Fprintf(os.Stderr, "Shared path changed: %s (%d file(s))\n", shared.Name, len(shared.Files))
+	}
+	if len(result.Root) > 0 {
+		fmt.Fprintf(os.Stderr, "Root files changed: %s\n", strings.Join(result.Root, ", "))
+	}
+}
+
+func newRunCommand() *cobra.Command {
+	var opts run.Options
+	cmd := describe(&cobra.Command{
+		Use:               "run [--affected <base>..<head>] [--repos a,b] -- <command> [args...]",
+		ValidArgsFunction: cobra.NoFileCompletions,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if cmd.ArgsLenAtDash() != 0 || len(args) == 0 {
+				return usageErrorf(cmd, "the command must follow -- (usage: %s)", cmd.UseLine())
+			}
+			// Keep stdout for the JSON document
+			opts.Output = os.Stdout
+			if jsonOutput {
+				opts.Output = os.Stderr
+			}
+			result, err := run.Run(gitClient, workDir, opts, args)
+			if err != nil {
+				return fmt.Errorf("failed to run %s: %w", args[0], err)
+			}
+			render(result, func() { printRunSummary(os.Stdout, result) })
+			if !result.Passed() {
+				return exitError{exitFailure}
+			}
+			return nil
+		},
+	}, run.Description())
+	cmd.Flags().StringVar(&opts.Affected, "affected", "", "only repositories changed in `base..head`")
+	cmd.Flags().StringSliceVar(&opts.Repos, "repos", nil, "only these repositories (comma separated)")
+	cmd.Flags().IntVarP(&opts.Parallel, "parallel", "j", 0, "maximum concurrent commands (default: number of CPUs)")
+	cmd.RegisterFlagCompletionFunc("repos", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return repoNames(completionDir(cmd), ""), cobra.ShellCompDirectiveNoFileComp
+	})
+	return cmd
+}
+
+func printRunSummary(out io.Writer, result *run.Result) {
+	if len(result.Tasks) == 0 {
+		fmt.Fprintln(out, "No repositories selected.")
+		return
+	}
+	fmt.Fprintln(out)
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "REPO\tEXIT\tDURATION\t")
+	failed := 0
+	for _, task := range result.Tasks {
+		status := fmt.Sprintf("%d", task.ExitCode)
+		if task.ExitCode != 0 {
+			failed++
+			if task.Error != "" {
+				status = "error"
+			}
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t\n", task.Repo, status, task.Duration.Round(time.Millisecond))
+	}
+	w.Flush()
+	if failed > 0 {
+		fmt.Fprintf(out, "%d of %d failed.\n", failed, len(result.Tasks))
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	splitcommit "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/grove/split-commit"
+	"github.com/kuchuk-borom-debbarma/GitGrove/src/internal/grove/verify"
+	"github.com/spf13/cobra"
+)
+
+// splitResult is the JSON output of split-commit: the commits created, or planned with --dry-run.
+type splitResult struct {
+	Groups []splitcommit.Group `json:"groups"`
+	DryRun bool                `json:"dry_run"`
+}
+
+func newSplitCommitCommand() *cobra.Command {
+	var message string
+	var edit, dryRun bool
+	cmd := describe(&cobra.Command{
+		Use:  "split-commit (-m <message> | --edit)",
+		Args: noArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if message == "" && !edit {
+				return usageErrorf(cmd, "a message (-m) or --edit is required")
+			}
+			if dryRun {
+				groups, err := splitcommit.Plan(gitClient, workDir, message)
+				if err != nil {
+					return fmt.Errorf("failed to plan split: %w", err)
+				}
+				render(splitResult{Groups: groups, DryRun: true}, func() {
+					for _, group := range groups {
+						fmt.Printf("%s (%d file(s))\n", group.Message, len(group.Files))
+						for _, file := range group.Files {
+							fmt.Printf("    %s\n", file)
+						}
+					}
+				})
+				return nil
+			}
+			groups, err := splitcommit.SplitCommit(gitClient, workDir, message, edit)
+			if err != nil {
+				return fmt.Errorf("failed to split commit: %w", err)
+			}
+			render(splitResult{Groups: groups}, func() {
+				for _, group := range groups {
+					fmt.Printf("%.7s %s (%d file(s))\n", group.SHA, strings.SplitN(group.Message, "\n", 2)[0], len(group.Files))
+				}
+			})
+			return nil
+		},
+	}, splitcommit.Description())
+	cmd.Flags().StringVarP(&message, "message", "m", "", "message for every commit ([repo] is prepended for repositories)")
+	cmd.Flags().BoolVarP(&edit, "edit", "e", false, "open the editor for each commit")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "show the groups without committing")
+	return cmd
+}
+
+func newVerifyCommand() *cobra.Command {
+	var useBaseConfig bool
+	cmd := describe(&cobra.Command{
+		Use:  "verify <base>..<head>",
+		Args: exactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			result, err := verify.Verify(gitClient, workDir, args[0], useBaseConfig)
+			if err != nil {
+				return fmt.Errorf("failed to verify %s: %w", args[0], err)
+			}
+			render(result, func() {
+				fmt.Printf("Checked %d of %d commit(s) in %s.\n", result.Checked, result.Commits, result.Range)
+				for _, v := range result.Violations {
+					fmt.Printf("  %.7s %s\n    [%s] %s\n", v.SHA, v.Subject, v.Kind, v.Message)
+				}
+				if result.Passed() {
+					fmt.Println("No violations.")
+				} else {
+					fmt.Printf("%d violation(s).\n", len(result.Violations))
+				}
+			})
+			if !result.Passed() {
+				return exitError{exitFailure}
+			}
+			return nil
+		},
+	}, verify.Description())
+	cmd.Flags().BoolVar(&useBaseConfig, "base-config", false, "check every commit against the base's gg.json")
+	return cmd
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"os"
+	"sort"
+	"strings"
+
+	preparemerge "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/grove/prepare-merge"
+	groveUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/grove"
+	"github.com/spf13/cobra"
+)
+
+func newCompletionCommand() *cobra.Command {
+	completion := &cobra.Command{
+		Use:   "completion <bash|zsh|fish>",
+		Short: "Generate the shell completion script",
+		Long: "Completion: Prints a completion script for bash, zsh or fish.\n" +
+			"- Completes commands, flags and the repository names registered in gg.json\n" +
+			"- bash: source <(gg completion bash)\n" +
+			"- zsh:  gg completion zsh > \"${fpath[1]}/_gg\"\n" +
+			"- fish: gg completion fish > ~/.config/fish/completions/gg.fish",
+		RunE: runGroup,
+	}
+	completion.AddCommand(
+		&cobra.Command{
+			Use:   "bash",
+			Short: "Generate the bash completion script",
+			Args:  noArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return cmd.Root().GenBashCompletionV2(os.Stdout, true)
+			},
+		},
+		&cobra.Command{
+			Use:   "zsh",
+			Short: "Generate the zsh completion script",
+			Args:  noArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return cmd.Root().GenZshCompletion(os.Stdout)
+			},
+		},
+		&cobra.Command{
+			Use:   "fish",
+			Short: "Generate the fish completion script",
+			Args:  noArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return cmd.Root().GenFishCompletion(os.Stdout, true)
+			},
+		},
+	)
+	return completion
+}
+
+// completeRepoNames completes the first argument with the registered repository names. gg.json is read
+// from the working tree, or from the trunk when on an orphan or feature branch (LoadWorkspaceConfig).
+func completeRepoNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return repoNames(completionDir(cmd), toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeRegister leaves the new repository's name to the user and completes its path with directories.
+func completeRegister(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 1 {
+		return nil, cobra.ShellCompDirectiveFilterDirs
+	}
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+func completeStrategy(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return []string{preparemerge.StrategyMerge, preparemerge.StrategyReplay}, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completionDir is the workspace root to complete in. PersistentPreRunE does not run for completion
+// requests, so -C is read from the parsed flags here.
+func completionDir(cmd *cobra.Command) string {
+	dir, _ := os.Getwd()
+	if chdir, _ := cmd.Flags().GetString("chdir"); chdir != "" {
+		dir = chdir
+	}
+	return groveUtil.WorkspaceRoot(gitClient, dir)
+}
+
+// repoNames returns the registered repositories starting with prefix, sorted. Errors yield no suggestions.
+func repoNames(dir string, prefix string) []string {
+	config, err := groveUtil.LoadWorkspaceConfig(gitClient, dir)
+	if err != nil {
+		return nil
+	}
+	names := []string{}
+	for name := range config.Repositories {
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/kuchuk-borom-debbarma/GitGrove/src/internal/grove/hooks"
+	gitUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/git"
+	"github.com/spf13/cobra"
+)
+
+// newHookCommand returns the entry points called by the installed git hook scripts
+// ("gg hook <name> <git hook arguments>"). Their arguments are passed through unparsed.
+// Hooks always run git itself: only git honors the GIT_DIR and GIT_INDEX_FILE it sets for them.
+func newHookCommand() *cobra.Command {
+	client := gitUtil.NewExecClient()
+	hook := &cobra.Command{
+		Use:    "hook <pre-commit|prepare-commit-msg|commit-msg|pre-push>",
+		Short:  "Entry points for the installed git hooks",
+		Hidden: true,
+		RunE:   runGroup,
+	}
+
+	hook.AddCommand(
+		&cobra.Command{
+			Use:                "pre-commit",
+			Short:              "Enforce atomic commits",
+			DisableFlagParsing: true,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return hooks.PreCommit(client)
+			},
+		},
+		&cobra.Command{
+			Use:                "prepare-commit-msg <msgFile> [source] [sha]",
+			Short:              "Prefix the commit message with the repository name",
+			DisableFlagParsing: true,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				if len(args) < 1 {
+					// Nothing to prepare
+					return nil
+				}
+				msgFile, source, sha := args[0], "", ""
+				if len(args) > 1 {
+					source = args[1]
+				}
+				if len(args) > 2 {
+					sha = args[2]
+				}
+				if err := hooks.PrepareCommitMsg(client, msgFile, source, sha); err != nil {
+					// A message that could not be prefixed must not abort the commit
+					fmt.Fprintf(os.Stderr, "Error in prepare-commit-msg: %v\n", err)
+				}
+				return nil
+			},
+		},
+		&cobra.Command{
+			Use:                "commit-msg <msgFile>",
+			Short:              "Validate the commit message",
+			DisableFlagParsing: true,
+			Args:               exactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return hooks.CommitMsg(client, args[0])
+			},
+		},
+		&cobra.Command{
+			Use:                "pre-push <remote> [url]",
+			Short:              "Validate every pushed commit (refs on stdin)",
+			DisableFlagParsing: true,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				remote := ""
+				if len(args) > 0 {
+					remote = args[0]
+				}
+				return hooks.PrePush(client, remote, os.Stdin)
+			},
+		},
+	)
+	return hook
+}
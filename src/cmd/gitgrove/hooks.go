@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	installhooks "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/grove/install-hooks"
+	"github.com/spf13/cobra"
+)
+
+// hookState is a hook in the JSON output of "gg hooks status".
+type hookState struct {
+	installhooks.HookStatus
+	Outdated bool `json:"outdated"`
+}
+
+type hooksStatusResult struct {
+	Hooks   []hookState `json:"hooks"`
+	Binary  string      `json:"binary"` // the gg binary the hooks run, empty if none is in PATH
+	Healthy bool        `json:"healthy"`
+}
+
+// hooksResult lists the hooks an install, upgrade or uninstall touched.
+type hooksResult struct {
+	Hooks []string `json:"hooks"`
+}
+
+// newHooksCommand manages the installed hook scripts (see newHookCommand for the hooks themselves).
+func newHooksCommand() *cobra.Command {
+	hooksCmd := describe(&cobra.Command{
+		Use:  "hooks",
+		RunE: runGroup,
+	}, installhooks.Description())
+	hooksCmd.Short = "Install, check, upgrade or remove the GitGrove git hooks"
+
+	hooksCmd.AddCommand(
+		&cobra.Command{
+			Use:   "status",
+			Short: "Show each hook, its version and whether gg is in PATH (exit 1 if action is needed)",
+			Args:  noArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				statuses, err := installhooks.Status(gitClient, workDir)
+				if err != nil {
+					return fmt.Errorf("failed to read hooks: %w", err)
+				}
+				result := hooksStatusResult{Hooks: []hookState{}, Binary: installhooks.ResolveBinary(), Healthy: true}
+				for _, status := range statuses {
+					result.Hooks = append(result.Hooks, hookState{status, status.Outdated()})
+					if !status.Installed || !status.Managed || status.Outdated() {
+						result.Healthy = false
+					}
+				}
+				if result.Binary == "" {
+					result.Healthy = false
+				}
+				render(result, func() { printHooksStatus(result) })
+				if !result.Healthy {
+					return exitError{exitFailure}
+				}
+				return nil
+			},
+		},
+		&cobra.Command{
+			Use:   "install",
+			Short: "Install the hooks (e.g. in a fresh clone), chaining existing ones",
+			Args:  noArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				if err := installhooks.Install(gitClient, workDir); err != nil {
+					return fmt.Errorf("failed to install hooks: %w", err)
+				}
+				names, err := hookNames(workDir)
+				if err != nil {
+					return err
+				}
+				render(hooksResult{Hooks: names}, func() {
+					fmt.Println("GitGrove hooks installed.")
+				})
+				return nil
+			},
+		},
+		&cobra.Command{
+			Use:   "upgrade",
+			Short: "Rewrite hooks written by an older gg",
+			Args:  noArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				upgraded, err := installhooks.Upgrade(gitClient, workDir)
+				if err != nil {
+					return fmt.Errorf("failed to upgrade hooks: %w", err)
+				}
+				if upgraded == nil {
+					upgraded = []string{}
+				}
+				render(hooksResult{Hooks: upgraded}, func() {
+					if len(upgraded) == 0 {
+						fmt.Println("All GitGrove hooks are up to date.")
+					} else {
+						fmt.Printf("Upgraded: %s\n", strings.Join(upgraded, ", "))
+					}
+				})
+				return nil
+			},
+		},
+		&cobra.Command{
+			Use:   "uninstall",
+			Short: "Remove the hooks and restore the ones they chained",
+			Args:  noArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				names, err := hookNames(workDir)
+				if err != nil {
+					return err
+				}
+				if err := installhooks.Uninstall(gitClient, workDir); err != nil {
+					return fmt.Errorf("failed to uninstall hooks: %w", err)
+				}
+				render(hooksResult{Hooks: names}, func() {
+					fmt.Println("GitGrove hooks removed; previous hooks restored.")
+				})
+				return nil
+			},
+		},
+	)
+	return hooksCmd
+}
+
+func printHooksStatus(result hooksStatusResult) {
+	for _, hook := range result.Hooks {
+		state := fmt.Sprintf("installed (version %d)", hook.Version)
+		switch {
+		case !hook.Installed:
+			state = "missing"
+		case !hook.Managed:
+			state = "not managed by GitGrove"
+		case hook.Outdated:
+			state = fmt.Sprintf("outdated (version %d, current %d)", hook.Version, installhooks.HookVersion)
+		}
+		if hook.Chained {
+			state += fmt.Sprintf(", chains %s%s (runs %s)", hook.Name, installhooks.ChainedSuffix, hook.Position)
+		}
+		fmt.Printf("  %-20s %s\n", hook.Name, state)
+	}
+	if result.Binary != "" {
+		fmt.Printf("Hooks run: %s\n", result.Binary)
+	} else {
+		fmt.Println("Warning: neither git-grove nor gg is in PATH; the hooks will skip their checks.")
+	}
+	if !result.Healthy {
+		fmt.Println("Run 'gg hooks install' (missing hooks) or 'gg hooks upgrade' (outdated hooks).")
+	}
+}
+
+// hookNames returns the names of the hooks GitGrove manages.
+func hookNames(repoPath string) ([]string, error) {
+	statuses, err := installhooks.Status(gitClient, repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read hooks: %w", err)
+	}
+	names := make([]string, 0, len(statuses))
+	for _, status := range statuses {
+		names = append(names, status.Name)
+	}
+	return names, nil
+}
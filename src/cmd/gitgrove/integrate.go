@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	openpr "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/grove/open-pr"
+	preparemerge "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/grove/prepare-merge"
+	"github.com/spf13/cobra"
+)
+
+func newPrepareMergeCommand() *cobra.Command {
+	var strategy string
+	cmd := describe(&cobra.Command{
+		Use:               "prepare-merge [repo-name]",
+		Args:              maxArgs(1),
+		ValidArgsFunction: completeRepoNames,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repoName := ""
+			if len(args) > 0 {
+				repoName = args[0]
+			}
+			result, err := preparemerge.PrepareMergeWithStrategy(gitClient, workDir, repoName, strategy)
+			if result != nil {
+				render(result, func() { printPrepareMerge(result) })
+			}
+			if err != nil {
+				if result != nil && jsonOutput {
+					// The result already carries the failed checks
+					return exitError{exitFailure}
+				}
+				return fmt.Errorf("failed to prepare merge: %w", err)
+			}
+			return nil
+		},
+	}, preparemerge.Description())
+	cmd.Flags().StringVar(&strategy, "strategy", "", "integration strategy: merge or replay (default from gg.json)")
+	cmd.RegisterFlagCompletionFunc("strategy", completeStrategy)
+	return cmd
+}
+
+func newOpenPRCommand() *cobra.Command {
+	return describe(&cobra.Command{
+		Use:  "open-pr",
+		Args: noArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			result, err := openpr.OpenPullRequest(gitClient, workDir)
+			if err != nil {
+				return fmt.Errorf("failed to open pull request: %w", err)
+			}
+			render(result, func() {
+				fmt.Printf("Opened pull request #%d: %s\n", result.Number, result.URL)
+			})
+			return nil
+		},
+	}, openpr.Description())
+}
+
+func printPrepareMerge(result *preparemerge.Result) {
+	fmt.Printf("Integrating %d commit(s) from %s into %s (%s strategy).\n", len(result.Commits), result.OrphanBranch, result.Trunk, result.Strategy)
+	if result.Checks != nil {
+		for _, check := range result.Checks.Results {
+			if check.Passed {
+				fmt.Printf("  ok      %s (%s)\n", check.Command, check.Duration.Round(time.Millisecond))
+			} else {
+				fmt.Printf("  FAILED  %s (exit %d)\n", check.Command, check.ExitCode)
+				if output := strings.TrimSpace(check.Output); output != "" {
+					fmt.Println(output)
+				}
+			}
+		}
+	}
+	for _, warning := range result.Warnings {
+		fmt.Printf("Warning: %s\n", warning)
+	}
+	fmt.Printf("Created branch %s.\n", result.Branch)
+	if result.Checks == nil || result.Checks.Passed {
+		fmt.Println("Review the changes and submit a Pull Request to merge into trunk.")
+	}
+}
@@ -1,181 +1,256 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/kuchuk-borom-debbarma/GitGrove/src/internal/grove/hooks"
-	"github.com/kuchuk-borom-debbarma/GitGrove/src/internal/grove/initialize"
-	preparemerge "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/grove/prepare-merge"
-	registerrepo "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/grove/register-repo"
-	grovesync "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/grove/sync"
 	"github.com/kuchuk-borom-debbarma/GitGrove/src/internal/tui"
 	gitUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/git"
 	groveUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/grove"
-	"github.com/kuchuk-borom-debbarma/GitGrove/src/model"
+	"github.com/spf13/cobra"
 )
 
 var BuildTime = "unknown"
 
+// Exit codes shared by every command.
+const (
+	exitOK      = 0 // success
+	exitFailure = 1 // the command failed or a check found problems
+	exitUsage   = 2 // unknown command, bad flags or arguments
+)
+
+// workDir is the workspace every command operates on: the root of the working tree containing the
+// current directory or the -C path (the directory itself outside a repository).
+var workDir string
+
+// jsonOutput is set by --json: commands print one JSON document on stdout instead of text.
+var jsonOutput bool
+
+// gitClient runs the git operations of every command. GG_GIT_BACKEND=go-git answers read-only
+// queries in process; hooks always use the exec client.
+var gitClient gitUtil.GitClient = gitUtil.NewExecClient()
+
+// usageError marks errors caused by how the command was invoked.
+type usageError struct {
+	err     error
+	cmdPath string // command whose --help explains the usage
+}
+
+func (e usageError) Error() string { return e.err.Error() }
+func (e usageError) Unwrap() error { return e.err }
+
+func usageErrorf(cmd *cobra.Command, format string, args ...any) error {
+	return usageError{fmt.Errorf(format, args...), cmd.CommandPath()}
+}
+
+// exitError ends the command with code without printing anything further (the command already
+// reported the outcome, e.g. verify listing its violations).
+type exitError struct{ code int }
+
+func (e exitError) Error() string { return fmt.Sprintf("exit status %d", e.code) }
+
 func main() {
-	if len(os.Args) > 1 {
-		switch os.Args[1] {
-		case "hook":
-			if len(os.Args) >= 3 {
-				switch os.Args[2] {
-				case "pre-commit":
-					if err := hooks.PreCommit(); err != nil {
-						fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-						os.Exit(1)
-					}
-					os.Exit(0)
-				case "prepare-commit-msg":
-					// args: <msgFile> <source> <sha>
-					// os.Args[0] = git-grove, [1]=hook, [2]=prepare-commit-msg, [3]=msgFile, [4]=source, [5]=sha
-					if len(os.Args) < 4 {
-						// minimal is msgFile
-						os.Exit(0)
-					}
-					msgFile := os.Args[3]
-					source := ""
-					if len(os.Args) > 4 {
-						source = os.Args[4]
-					}
-					sha := ""
-					if len(os.Args) > 5 {
-						sha = os.Args[5]
-					}
-					if err := hooks.PrepareCommitMsg(msgFile, source, sha); err != nil {
-						fmt.Fprintf(os.Stderr, "Error in prepare-commit-msg: %v\n", err)
-						// We don't exit 1 on hook error usually unless we want to abort commit,
-						// but message prep failure might be annoying if it aborts.
-						// pre-commit hook aborts commit. prepare-commit-msg just preps it.
-						// If we fail to prep, maybe we should let it pass?
-						// But if we fail we might want to know.
-						// For now, let's just print error and exit 0 to allow commit to proceed without mod?
-						// Or Exit 1 to stop? Let's generic exit 1 if critical.
-						os.Exit(0)
-					}
-					os.Exit(0)
+	os.Exit(execute(os.Args[1:]))
+}
+
+func execute(args []string) int {
+	root := newRootCommand()
+	root.SetArgs(args)
+	err := root.Execute()
+	if err == nil {
+		return exitOK
+	}
+
+	var exit exitError
+	if errors.As(err, &exit) {
+		return exit.code
+	}
+	code := exitFailure
+	fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	var usage usageError
+	if errors.As(err, &usage) {
+		fmt.Fprintf(os.Stderr, "Run '%s --help' for usage.\n", usage.cmdPath)
+		code = exitUsage
+	}
+	if jsonOutput {
+		printJSON(jsonError{Error: err.Error(), ExitCode: code})
+	}
+	return code
+}
+
+// jsonError is printed on stdout in --json mode when a command fails without a result.
+type jsonError struct {
+	Error    string `json:"error"`
+	ExitCode int    `json:"exit_code"`
+}
+
+// render prints v as JSON in --json mode and otherwise calls human to print the text output.
+// Both modes render the same value, so the schema is whatever the command's result marshals to.
+func render(v any, human func()) {
+	if jsonOutput {
+		printJSON(v)
+		return
+	}
+	human()
+}
+
+func printJSON(v any) {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		encoder.Encode(jsonError{Error: err.Error(), ExitCode: exitFailure})
+	}
+}
+
+// warn prints a non-fatal problem; in --json mode warnings are part of the result instead.
+func warn(format string, args ...any) string {
+	message := fmt.Sprintf(format, args...)
+	if !jsonOutput {
+		fmt.Printf("Warning: %s\n", message)
+	}
+	return message
+}
+
+func newRootCommand() *cobra.Command {
+	var chdir string
+	root := &cobra.Command{
+		Use:   "gg",
+		Short: "GitGrove: isolated per-repository histories inside a monorepo",
+		Long: "GitGrove keeps a monorepo trunk and an orphan branch per registered repository in sync.\n" +
+			"Run gg without a command to open the interactive TUI.",
+		Version:       BuildTime,
+		Args:          noArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if chdir != "" {
+				if err := os.Chdir(chdir); err != nil {
+					return usageErrorf(cmd, "cannot use -C %s: %w", chdir, err)
 				}
 			}
-			fmt.Println("Usage: git-grove hook <pre-commit|prepare-commit-msg>")
-			os.Exit(1)
-		case "init":
-			cwd, _ := os.Getwd()
-			atomic := false
-			// Simple flag parsing for init command
-			for _, arg := range os.Args[2:] {
-				if arg == "--atomic" {
-					atomic = true
+			if backend := os.Getenv("GG_GIT_BACKEND"); backend != "" {
+				client, err := gitUtil.NewClient(backend)
+				if err != nil {
+					return err
 				}
+				gitClient = client
 			}
-
-			if err := initialize.Initialize(cwd, atomic); err != nil {
-				fmt.Fprintf(os.Stderr, "Error initializing GitGrove: %v\n", err)
-				os.Exit(1)
-			}
-			fmt.Println("GitGrove initialized successfully!")
-			os.Exit(0)
-		case "prepare-merge":
-			cwd, _ := os.Getwd()
-			repoName := ""
-			if len(os.Args) > 2 {
-				repoName = os.Args[2]
+			cwd, err := os.Getwd()
+			if err != nil {
+				return err
 			}
-			if err := preparemerge.PrepareMerge(cwd, repoName); err != nil {
-				fmt.Fprintf(os.Stderr, "Error preparing merge: %v\n", err)
-				os.Exit(1)
+			workDir = groveUtil.WorkspaceRoot(gitClient, cwd)
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// The TUI refreshes every second: serve its queries in process unless a backend was chosen
+			client := gitClient
+			if os.Getenv("GG_GIT_BACKEND") == "" {
+				client = gitUtil.NewGoGitClient(gitClient)
 			}
-			os.Exit(0)
-		case "register":
-			if len(os.Args) < 4 {
-				fmt.Println("Usage: gg register <name> <path>")
-				os.Exit(1)
-			}
-			cwd, _ := os.Getwd()
-			name := os.Args[2]
-			path := os.Args[3]
-			repo := model.GGRepo{Name: name, Path: path}
-			if err := registerrepo.RegisterRepo([]model.GGRepo{repo}, cwd); err != nil {
-				fmt.Fprintf(os.Stderr, "Error registering repo: %v\n", err)
-				os.Exit(1)
-			}
-			fmt.Printf("Successfully registered repo '%s'\n", name)
-			os.Exit(0)
-		case "checkout":
-			if len(os.Args) < 3 {
-				fmt.Println("Usage: gg checkout <repo-name>")
-				os.Exit(1)
-			}
-			cwd, _ := os.Getwd()
-			repoName := os.Args[2]
-
-			// Determine Trunk
-			trunk, err := groveUtil.GetContextTrunk(cwd)
-			if err != nil || trunk == "" {
-				// Try falling back to current branch if we are on trunk
-				cb, _ := gitUtil.CurrentBranch(cwd)
-				if cb != "" {
-					trunk = cb
-				} else {
-					fmt.Fprintf(os.Stderr, "Error: Could not determine trunk branch. Ensure you are in a GitGrove workspace.\n")
-					os.Exit(1)
-				}
+			p := tea.NewProgram(tui.InitialModel(BuildTime, client), tea.WithAltScreen())
+			if _, err := p.Run(); err != nil {
+				return fmt.Errorf("alas, there's been an error: %w", err)
 			}
+			return nil
+		},
+	}
+	root.PersistentFlags().StringVarP(&chdir, "chdir", "C", "", "run as if gg was started in `path`")
+	root.PersistentFlags().BoolVar(&jsonOutput, "json", false, "print the result as JSON on stdout")
+	root.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return usageError{err, cmd.CommandPath()}
+	})
+	root.CompletionOptions.DisableDefaultCmd = true
+	root.SetVersionTemplate("gg built {{.Version}}\n")
 
-			// Construct target branch: gg/<trunk>/<repo>
-			targetBranch := fmt.Sprintf("gg/%s/%s", trunk, repoName)
+	root.AddCommand(
+		newHookCommand(),
+		newInitCommand(),
+		newRegisterCommand(),
+		newCheckoutCommand(),
+		newTrunkCommand(),
+		newResetCommand(),
+		newPendingCommand(),
+		newStatusCommand(),
+		newLogCommand(),
+		newDiffCommand(),
+		newPrepareMergeCommand(),
+		newOpenPRCommand(),
+		newScopeCommand(),
+		newHooksCommand(),
+		newSplitCommitCommand(),
+		newVerifyCommand(),
+		newAffectedCommand(),
+		newRunCommand(),
+		newCompletionCommand(),
+	)
+	disableFileCompletion(root)
+	return root
+}
 
-			if err := gitUtil.Checkout(cwd, targetBranch); err != nil {
-				fmt.Fprintf(os.Stderr, "Error checking out %s: %v\n", targetBranch, err)
-				os.Exit(1)
-			}
+// disableFileCompletion stops the shell from offering file names for commands that do not complete
+// their own arguments (none of them take files, except register's path).
+func disableFileCompletion(cmd *cobra.Command) {
+	if cmd.ValidArgsFunction == nil {
+		cmd.ValidArgsFunction = cobra.NoFileCompletions
+	}
+	for _, sub := range cmd.Commands() {
+		disableFileCompletion(sub)
+	}
+}
 
-			// Clean artifacts
-			if err := gitUtil.Clean(cwd); err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: Checkout succeeded but clean failed: %v\n", err)
-			}
+// describe fills Short and Long from a grove package Description(): "Title: summary.\n- details".
+func describe(cmd *cobra.Command, description string) *cobra.Command {
+	first, _, _ := strings.Cut(description, "\n")
+	if _, summary, ok := strings.Cut(first, ": "); ok {
+		first = summary
+	}
+	cmd.Short = strings.TrimSuffix(first, ".")
+	cmd.Long = description
+	return cmd
+}
 
-			// Set sticky context
-			_ = groveUtil.SetContextRepo(cwd, repoName)
-			_ = groveUtil.SetContextTrunk(cwd, trunk)
-			_ = groveUtil.SetContextOrphan(cwd, targetBranch)
-
-			fmt.Printf("Switched to orphan branch: %s\n", targetBranch)
-			os.Exit(0)
-		case "reset":
-			cwd, _ := os.Getwd()
-			// Let ResetOrphanToTrunk infer context
-			if err := grovesync.ResetOrphanToTrunk(cwd, "", "", ""); err != nil {
-				fmt.Fprintf(os.Stderr, "Error resetting to trunk: %v\n", err)
-				os.Exit(1)
-			}
-			fmt.Println("Successfully reset to trunk.")
-			os.Exit(0)
-		case "trunk":
-			cwd, _ := os.Getwd()
-			trunk, err := groveUtil.GetContextTrunk(cwd)
-			if err != nil || trunk == "" {
-				fmt.Fprintf(os.Stderr, "Error: Unknown trunk branch. Are you in a GitGrove orphan branch?\n")
-				os.Exit(1)
-			}
-			if err := gitUtil.Checkout(cwd, trunk); err != nil {
-				fmt.Fprintf(os.Stderr, "Error returning to trunk: %v\n", err)
-				os.Exit(1)
-			}
-			// Clear context
-			groveUtil.ClearAllContext(cwd)
+// runGroup is the RunE of commands that only group subcommands: unknown subcommands are usage
+// errors, and the bare group prints its help.
+func runGroup(cmd *cobra.Command, args []string) error {
+	if len(args) > 0 {
+		return usageErrorf(cmd, "unknown command %q for %q", args[0], cmd.CommandPath())
+	}
+	cmd.Help()
+	return exitError{exitUsage}
+}
 
-			fmt.Printf("Returned to trunk branch: %s\n", trunk)
-			os.Exit(0)
+// Argument validators that report usage errors (exit code 2).
+
+func noArgs(cmd *cobra.Command, args []string) error {
+	if len(args) > 0 {
+		if cmd.HasSubCommands() {
+			return usageErrorf(cmd, "unknown command %q for %q", args[0], cmd.CommandPath())
 		}
+		return usageErrorf(cmd, "%s takes no arguments", cmd.CommandPath())
 	}
+	return nil
+}
 
-	p := tea.NewProgram(tui.InitialModel(BuildTime), tea.WithAltScreen())
-	if _, err := p.Run(); err != nil {
-		fmt.Printf("Alas, there's been an error: %v", err)
-		os.Exit(1)
+func exactArgs(n int) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) != n {
+			return usageErrorf(cmd, "%s expects %d argument(s), got %d (usage: %s)", cmd.CommandPath(), n, len(args), cmd.UseLine())
+		}
+		return nil
+	}
+}
+
+func maxArgs(n int) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) > n {
+			return usageErrorf(cmd, "%s accepts at most %d argument(s), got %d (usage: %s)", cmd.CommandPath(), n, len(args), cmd.UseLine())
+		}
+		return nil
 	}
 }
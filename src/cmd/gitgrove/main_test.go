@@ -0,0 +1,201 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kuchuk-borom-debbarma/GitGrove/src/internal/grove/initialize"
+	registerrepo "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/grove/register-repo"
+	"github.com/kuchuk-borom-debbarma/GitGrove/src/internal/testutil"
+)
+
+func TestExecute_ExitCodes(t *testing.T) {
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+
+	dir := testutil.GitRepo(t)
+
+	cases := []struct {
+		name string
+		args []string
+		code int
+	}{
+		{"unknown command", []string{"bogus"}, exitUsage},
+		{"unknown subcommand", []string{"hooks", "bogus"}, exitUsage},
+		{"unknown flag", []string{"init", "--bogus"}, exitUsage},
+		{"missing argument", []string{"register", "only-name"}, exitUsage},
+		{"bad -C path", []string{"-C", dir + "/missing", "scope"}, exitUsage},
+		{"help", []string{"verify", "--help"}, exitOK},
+		{"init via -C", []string{"-C", dir, "init"}, exitOK},
+		{"init twice", []string{"-C", dir, "init"}, exitFailure},
+		{"hook entry point", []string{"-C", dir, "hook", "pre-commit"}, exitOK},
+	}
+	for _, c := range cases {
+		if code := execute(c.args); code != c.code {
+			t.Errorf("%s: expected exit code %d, got %d", c.name, c.code, code)
+		}
+		os.Chdir(wd)
+	}
+
+	if _, err := os.Stat(dir + "/.gg/gg.json"); err != nil {
+		t.Errorf("expected -C to initialize %s: %v", dir, err)
+	}
+}
+
+// captureStdout runs execute and returns its exit code and stdout.
+func captureStdout(t *testing.T, args ...string) (int, []byte) {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	code := execute(args)
+	os.Stdout = stdout
+	w.Close()
+	out, _ := io.ReadAll(r)
+	return code, out
+}
+
+func TestExecute_JSON(t *testing.T) {
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+
+	dir := testutil.GitRepo(t)
+
+	code, out := captureStdout(t, "-C", dir, "--json", "init")
+	var initialized initialize.Result
+	if code != exitOK || json.Unmarshal(out, &initialized) != nil {
+		t.Fatalf("init --json: exit %d, output %q", code, out)
+	}
+	if initialized.Trunk != "main" || initialized.AtomicCommit {
+		t.Errorf("unexpected init result: %+v", initialized)
+	}
+	os.Chdir(wd)
+
+	testutil.WriteFile(t, dir, "svc/main.go", "package main")
+	testutil.Git(t, dir, "add", "svc")
+	testutil.Git(t, dir, "-c", "core.hooksPath=/dev/null", "commit", "-m", "add svc")
+
+	code, out = captureStdout(t, "-C", dir, "register", "svc", "svc", "--json")
+	var registered registerrepo.Result
+	if code != exitOK || json.Unmarshal(out, &registered) != nil {
+		t.Fatalf("register --json: exit %d, output %q", code, out)
+	}
+	if registered.Trunk != "main" || len(registered.Repos) != 1 || registered.Repos[0].OrphanBranch != "gg/main/svc" {
+		t.Errorf("unexpected register result: %+v", registered)
+	}
+	os.Chdir(wd)
+
+	// Failures are reported as JSON too
+	code, out = captureStdout(t, "-C", dir, "--json", "init")
+	var failure jsonError
+	if code != exitFailure || json.Unmarshal(out, &failure) != nil || failure.Error == "" || failure.ExitCode != exitFailure {
+		t.Errorf("init twice --json: exit %d, output %q", code, out)
+	}
+}
+
+func TestCompletion_RepoNames(t *testing.T) {
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+
+	dir := testutil.GitRepo(t)
+	if code := execute([]string{"-C", dir, "init"}); code != exitOK {
+		t.Fatalf("init failed with exit code %d", code)
+	}
+	os.Chdir(wd)
+	testutil.Git(t, dir, "config", "core.hooksPath", "/dev/null")
+	for _, name := range []string{"billing-api", "billing-web", "search"} {
+		testutil.WriteFile(t, dir, name+"/main.go", "package main")
+	}
+	testutil.Git(t, dir, "add", ".")
+	testutil.Git(t, dir, "commit", "-m", "add services")
+	for _, name := range []string{"billing-api", "billing-web", "search"} {
+		if code := execute([]string{"-C", dir, "register", name, name}); code != exitOK {
+			t.Fatalf("register %s failed with exit code %d", name, code)
+		}
+		os.Chdir(wd)
+	}
+
+	complete := func(args ...string) []string {
+		t.Helper()
+		_, out := captureStdout(t, append([]string{"__complete", "-C", dir}, args...)...)
+		os.Chdir(wd)
+		var suggestions []string
+		for _, line := range strings.Split(string(out), "\n") {
+			if line != "" && !strings.HasPrefix(line, ":") {
+				suggestions = append(suggestions, line)
+			}
+		}
+		return suggestions
+	}
+
+	if got := complete("checkout", "billing-"); strings.Join(got, ",") != "billing-api,billing-web" {
+		t.Errorf("checkout billing-: got %v", got)
+	}
+	// On the orphan branch gg.json is read from the trunk
+	exec.Command("git", "-C", dir, "checkout", "-q", "gg/main/search").Run()
+	if got := complete("prepare-merge", ""); len(got) != 3 {
+		t.Errorf("prepare-merge on an orphan branch: got %v", got)
+	}
+	if got := complete("prepare-merge", "--strategy", "r"); len(got) != 2 {
+		t.Errorf("--strategy: got %v", got)
+	}
+}
+
+func TestExecute_WorkspaceDiscovery(t *testing.T) {
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+
+	dir, _ := filepath.EvalSymlinks(testutil.GitRepo(t))
+	os.MkdirAll(dir+"/svc/internal", 0755)
+	testutil.WriteFile(t, dir, "svc/main.go", "package main")
+	testutil.Git(t, dir, "add", "svc")
+	testutil.Git(t, dir, "-c", "core.hooksPath=/dev/null", "commit", "-m", "add svc")
+
+	// From a subdirectory: the workspace is the repository root
+	code, out := captureStdout(t, "-C", dir+"/svc/internal", "--json", "init")
+	var initialized initialize.Result
+	if code != exitOK || json.Unmarshal(out, &initialized) != nil || initialized.Path != dir {
+		t.Fatalf("init from a subdirectory: exit %d, output %q", code, out)
+	}
+	os.Chdir(wd)
+	if _, err := os.Stat(dir + "/.gg/gg.json"); err != nil {
+		t.Errorf("expected gg.json at the repository root: %v", err)
+	}
+	if code := execute([]string{"-C", dir + "/svc", "register", "svc", "svc"}); code != exitOK {
+		t.Fatalf("register from a subdirectory: exit %d", code)
+	}
+	os.Chdir(wd)
+
+	// From a linked worktree: hooks and sticky context are those of the main repository
+	worktree := filepath.Join(filepath.Dir(dir), "worktree")
+	if err := exec.Command("git", "-C", dir, "worktree", "add", "-q", "-b", "feature", worktree).Run(); err != nil {
+		t.Fatalf("git worktree add failed: %v", err)
+	}
+	// (unhealthy without a gg binary in PATH, which only changes the exit code)
+	code, out = captureStdout(t, "-C", worktree+"/svc", "--json", "hooks", "status")
+	var hooksStatus hooksStatusResult
+	if code == exitUsage || json.Unmarshal(out, &hooksStatus) != nil || len(hooksStatus.Hooks) == 0 {
+		t.Fatalf("hooks status from a worktree: exit %d, output %q", code, out)
+	}
+	os.Chdir(wd)
+	for _, hook := range hooksStatus.Hooks {
+		if !hook.Installed || filepath.Dir(hook.Path) != filepath.Join(dir, ".git", "hooks") {
+			t.Errorf("expected %s installed in the common git dir, got %+v", hook.Name, hook)
+		}
+	}
+	if code := execute([]string{"-C", worktree + "/svc", "scope", "svc"}); code != exitOK {
+		t.Fatalf("scope from a worktree: exit %d", code)
+	}
+	os.Chdir(wd)
+	if scope, _ := exec.Command("git", "-C", dir, "config", "gitgrove.context.scope").Output(); strings.TrimSpace(string(scope)) != "svc" {
+		t.Errorf("expected the scope in the common config, got %q", scope)
+	}
+}
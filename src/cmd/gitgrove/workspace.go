@@ -0,0 +1,435 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/kuchuk-borom-debbarma/GitGrove/src/internal/grove/diff"
+	"github.com/kuchuk-borom-debbarma/GitGrove/src/internal/grove/initialize"
+	grovelog "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/grove/log"
+	registerrepo "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/grove/register-repo"
+	"github.com/kuchuk-borom-debbarma/GitGrove/src/internal/grove/scope"
+	"github.com/kuchuk-borom-debbarma/GitGrove/src/internal/grove/status"
+	grovesync "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/grove/sync"
+	groveUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/grove"
+	"github.com/kuchuk-borom-debbarma/GitGrove/src/model"
+	"github.com/spf13/cobra"
+)
+
+// JSON results of the workspace commands.
+
+type checkoutResult struct {
+	Repo     string   `json:"repo"`
+	Trunk    string   `json:"trunk"`
+	Branch   string   `json:"branch"`
+	Warnings []string `json:"warnings"`
+}
+
+type trunkResult struct {
+	Trunk string `json:"trunk"`
+}
+
+type resetResult struct {
+	Repo             string   `json:"repo"`
+	Trunk            string   `json:"trunk"`
+	DiscardedCommits []string `json:"discarded_commits"`
+	Warnings         []string `json:"warnings"`
+}
+
+type commitSummary struct {
+	SHA     string `json:"sha"`
+	Subject string `json:"subject"`
+}
+
+type pendingResult struct {
+	Repo            string                       `json:"repo"`
+	Trunk           string                       `json:"trunk"`
+	LastIntegration *groveUtil.IntegrationRecord `json:"last_integration"` // null if never integrated
+	Pending         []commitSummary              `json:"pending"`
+}
+
+type scopeResult struct {
+	Scope string `json:"scope"` // empty when no scope is active
+}
+
+func newInitCommand() *cobra.Command {
+	var atomic bool
+	cmd := describe(&cobra.Command{
+		Use:  "init",
+		Args: noArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			result, err := initialize.Initialize(gitClient, workDir, atomic)
+			if err != nil {
+				return fmt.Errorf("failed to initialize GitGrove: %w", err)
+			}
+			render(result, func() {
+				fmt.Println("GitGrove initialized successfully!")
+			})
+			return nil
+		},
+	}, initialize.Description())
+	cmd.Flags().BoolVar(&atomic, "atomic", false, "enable atomic commit enforcement")
+	return cmd
+}
+
+func newRegisterCommand() *cobra.Command {
+	return describe(&cobra.Command{
+		Use:               "register <name> <path>",
+		Args:              exactArgs(2),
+		ValidArgsFunction: completeRegister,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repo := model.GGRepo{Name: args[0], Path: args[1]}
+			result, err := registerrepo.RegisterRepo(gitClient, []model.GGRepo{repo}, workDir)
+			if err != nil {
+				return fmt.Errorf("failed to register repo: %w", err)
+			}
+			render(result, func() {
+				fmt.Printf("Successfully registered repo '%s'\n", repo.Name)
+			})
+			return nil
+		},
+	}, registerrepo.Description())
+}
+
+func newCheckoutCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:               "checkout <repo-name>",
+		Short:             "Switch to a repository's orphan branch",
+		ValidArgsFunction: completeRepoNames,
+		Long: "Checkout: Switches to the orphan branch gg/<trunk>/<repo>.\n" +
+			"- Cleans files left over from the trunk\n" +
+			"- Sets the sticky context so feature branches keep the [repo] prefix",
+		Args: exactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repoName := args[0]
+
+			// Determine Trunk
+			trunk, err := groveUtil.GetContextTrunk(gitClient, workDir)
+			if err != nil || trunk == "" {
+				// Try falling back to current branch if we are on trunk
+				trunk, _ = gitClient.CurrentBranch(workDir)
+				if trunk == "" {
+					return fmt.Errorf("could not determine trunk branch; ensure you are in a GitGrove workspace")
+				}
+			}
+
+			// Construct target branch: gg/<trunk>/<repo>
+			targetBranch := fmt.Sprintf("gg/%s/%s", trunk, repoName)
+			if err := gitClient.Checkout(workDir, targetBranch); err != nil {
+				return fmt.Errorf("failed to check out %s: %w", targetBranch, err)
+			}
+
+			result := checkoutResult{Repo: repoName, Trunk: trunk, Branch: targetBranch, Warnings: []string{}}
+
+			// Clean artifacts
+			if err := gitClient.Clean(workDir); err != nil {
+				result.Warnings = append(result.Warnings, warn("checkout succeeded but clean failed: %v", err))
+			}
+
+			// Set sticky context
+			_ = groveUtil.SetContextRepo(gitClient, workDir, repoName)
+			_ = groveUtil.SetContextTrunk(gitClient, workDir, trunk)
+			_ = groveUtil.SetContextOrphan(gitClient, workDir, targetBranch)
+
+			render(result, func() {
+				fmt.Printf("Switched to orphan branch: %s\n", targetBranch)
+			})
+			return nil
+		},
+	}
+}
+
+func newTrunkCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "trunk",
+		Short: "Return to the trunk branch and clear the sticky context",
+		Args:  noArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			trunk, err := groveUtil.GetContextTrunk(gitClient, workDir)
+			if err != nil || trunk == "" {
+				return fmt.Errorf("unknown trunk branch; are you in a GitGrove orphan branch?")
+			}
+			if err := gitClient.Checkout(workDir, trunk); err != nil {
+				return fmt.Errorf("failed to return to trunk: %w", err)
+			}
+			// Clear context
+			groveUtil.ClearAllContext(gitClient, workDir)
+
+			render(trunkResult{Trunk: trunk}, func() {
+				fmt.Printf("Returned to trunk branch: %s\n", trunk)
+			})
+			return nil
+		},
+	}
+}
+
+func newResetCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "reset",
+		Short: "Hard reset the orphan branch to the trunk's version of the repository",
+		Long: "Reset: Rebuilds the current orphan branch from the trunk.\n" +
+			"- Discards local changes and commits that were not integrated (a warning shows how many)",
+		Args: noArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			trunk, repoName := groveUtil.ResolveRepoContext(gitClient, workDir)
+			result := resetResult{Repo: repoName, Trunk: trunk, DiscardedCommits: []string{}, Warnings: []string{}}
+			if pending, err := grovesync.UnintegratedCommits(gitClient, workDir, "", ""); err == nil && len(pending) > 0 {
+				result.DiscardedCommits = pending
+				result.Warnings = append(result.Warnings, warn("discarding %d commit(s) not yet integrated into trunk.", len(pending)))
+			}
+			// Let ResetOrphanToTrunk infer context
+			if err := grovesync.ResetOrphanToTrunk(gitClient, workDir, "", "", ""); err != nil {
+				return fmt.Errorf("failed to reset to trunk: %w", err)
+			}
+			render(result, func() {
+				fmt.Println("Successfully reset to trunk.")
+			})
+			return nil
+		},
+	}
+}
+
+func newPendingCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:               "pending [repo-name]",
+		Short:             "List orphan commits not yet integrated into the trunk",
+		Args:              maxArgs(1),
+		ValidArgsFunction: completeRepoNames,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			trunk, repoName := groveUtil.ResolveRepoContext(gitClient, workDir)
+			if len(args) > 0 {
+				repoName = args[0]
+			}
+			if trunk == "" {
+				trunk, _ = gitClient.CurrentBranch(workDir)
+			}
+			if repoName == "" {
+				return usageErrorf(cmd, "no repository context; usage: %s", cmd.UseLine())
+			}
+			pending, err := grovesync.UnintegratedCommits(gitClient, workDir, trunk, repoName)
+			if err != nil {
+				return fmt.Errorf("failed to compute pending commits: %w", err)
+			}
+			result := pendingResult{Repo: repoName, Trunk: trunk, Pending: []commitSummary{}}
+			if last, err := groveUtil.LastIntegration(gitClient, workDir, trunk, repoName); err == nil {
+				result.LastIntegration = last
+			}
+			for _, sha := range pending {
+				subject := ""
+				if info, err := gitClient.GetCommitInfo(workDir, sha); err == nil {
+					subject = strings.SplitN(info.Message, "\n", 2)[0]
+				}
+				result.Pending = append(result.Pending, commitSummary{SHA: sha, Subject: subject})
+			}
+
+			render(result, func() {
+				if last := result.LastIntegration; last != nil {
+					fmt.Printf("Last integration into %s: %s (orphan commit %.7s)\n", trunk, last.Date.Format("2006-01-02 15:04"), last.OrphanCommit)
+				} else {
+					fmt.Printf("'%s' has never been integrated into %s.\n", repoName, trunk)
+				}
+				if len(result.Pending) == 0 {
+					fmt.Println("Nothing pending.")
+					return
+				}
+				fmt.Printf("%d commit(s) pending:\n", len(result.Pending))
+				for _, commit := range result.Pending {
+					fmt.Printf("  %.7s %s\n", commit.SHA, commit.Subject)
+				}
+			})
+			return nil
+		},
+	}
+}
+
+func newScopeCommand() *cobra.Command {
+	var clear bool
+	cmd := describe(&cobra.Command{
+		Use:               "scope [repo-name]",
+		Args:              maxArgs(1),
+		ValidArgsFunction: completeRepoNames,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch {
+			case clear:
+				if err := scope.ClearScope(gitClient, workDir); err != nil {
+					return fmt.Errorf("failed to clear scope: %w", err)
+				}
+				render(scopeResult{}, func() {
+					fmt.Println("Scope cleared.")
+				})
+			case len(args) == 0:
+				active := scope.GetScope(gitClient, workDir)
+				render(scopeResult{Scope: active}, func() {
+					if active != "" {
+						fmt.Printf("Active scope: %s\n", active)
+					} else {
+						fmt.Println("No active scope.")
+					}
+				})
+			default:
+				if err := scope.SetScope(gitClient, workDir, args[0]); err != nil {
+					return fmt.Errorf("failed to set scope: %w", err)
+				}
+				render(scopeResult{Scope: args[0]}, func() {
+					fmt.Printf("Scope locked to '%s'. Commits outside it will be rejected until 'gg scope --clear'.\n", args[0])
+				})
+			}
+			return nil
+		},
+	}, scope.Description())
+	cmd.Flags().BoolVar(&clear, "clear", false, "remove the scope lock")
+	return cmd
+}
+
+func newStatusCommand() *cobra.Command {
+	return describe(&cobra.Command{
+		Use:  "status",
+		Args: noArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			result, err := status.GetStatus(gitClient, workDir)
+			if err != nil {
+				return fmt.Errorf("failed to read status: %w", err)
+			}
+			render(result, func() { printStatus(result) })
+			return nil
+		},
+	}, status.Description())
+}
+
+func printStatus(result *status.Status) {
+	fmt.Printf("On branch %s (trunk: %s)\n", result.Branch, result.Trunk)
+	if len(result.Repos) == 0 {
+		fmt.Println("No registered repositories.")
+	} else {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "  REPO\tPATH\tORPHAN\tAHEAD\tBEHIND\tMERGE-PREP\tLAST INTEGRATION\t")
+		for _, repo := range result.Repos {
+			marker := " "
+			if repo.Current {
+				marker = "*"
+			}
+			orphan := "yes"
+			if !repo.OrphanExists {
+				orphan = "missing"
+			}
+			last := "never"
+			if repo.LastIntegration != nil {
+				last = repo.LastIntegration.Date.Format("2006-01-02 15:04")
+			}
+			notes := []string{}
+			if repo.Checks != nil {
+				notes = append(notes, repo.Checks.Summary())
+			}
+			if repo.Dirty {
+				notes = append(notes, "dirty")
+			}
+			if repo.Error != "" {
+				notes = append(notes, "error: "+repo.Error)
+			}
+			fmt.Fprintf(w, "%s %s\t%s\t%s\t%d\t%d\t%d\t%s\t%s\n", marker, repo.Name, repo.Path, orphan,
+				repo.Ahead, repo.Behind, len(repo.MergePrep), last, strings.Join(notes, ", "))
+		}
+		w.Flush()
+	}
+	if result.Dirty() {
+		fmt.Printf("Working tree has %d uncommitted change(s).\n", len(result.DirtyFiles))
+	}
+	for _, reason := range result.StaleContext {
+		fmt.Printf("Stale context: %s\n", reason)
+	}
+}
+
+func newLogCommand() *cobra.Command {
+	var opts grovelog.Options
+	var oneline bool
+	cmd := describe(&cobra.Command{
+		Use:               "log [repo-name]",
+		Args:              maxArgs(1),
+		ValidArgsFunction: completeRepoNames,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repoName := ""
+			if len(args) > 0 {
+				repoName = args[0]
+			}
+			entries, err := grovelog.Log(gitClient, workDir, "", repoName, opts)
+			if err != nil {
+				return fmt.Errorf("failed to read history: %w", err)
+			}
+			render(entries, func() { printLog(entries, oneline) })
+			return nil
+		},
+	}, grovelog.Description())
+	cmd.Flags().StringVar(&opts.Since, "since", "", "only commits more recent than `date` (as git log --since)")
+	cmd.Flags().StringVar(&opts.Author, "author", "", "only commits whose author matches `pattern`")
+	cmd.Flags().BoolVar(&oneline, "oneline", false, "one line per commit")
+	return cmd
+}
+
+func printLog(entries []grovelog.Entry, oneline bool) {
+	for i, entry := range entries {
+		if oneline {
+			fmt.Printf("%.7s %-8s %s\n", entry.SHA(), "["+entry.Location+"]", entry.Subject)
+			continue
+		}
+		if i > 0 {
+			fmt.Println()
+		}
+		where := entry.Location
+		if entry.TrunkSHA != "" && entry.OrphanSHA != "" && entry.TrunkSHA != entry.OrphanSHA {
+			where += fmt.Sprintf(", orphan %.7s", entry.OrphanSHA)
+		}
+		fmt.Printf("commit %s (%s)\n", entry.SHA(), where)
+		fmt.Printf("Author: %s <%s>\n", entry.AuthorName, entry.AuthorEmail)
+		fmt.Printf("Date:   %s\n\n", entry.Date.Format("Mon Jan 2 15:04:05 2006 -0700"))
+		fmt.Printf("    %s\n", entry.Subject)
+		if entry.Body != "" {
+			fmt.Println()
+			for _, line := range strings.Split(entry.Body, "\n") {
+				fmt.Printf("    %s\n", line)
+			}
+		}
+	}
+}
+
+func newDiffCommand() *cobra.Command {
+	var opts diff.Options
+	var stat, nameOnly bool
+	cmd := describe(&cobra.Command{
+		Use:               "diff [repo-name]",
+		Args:              maxArgs(1),
+		ValidArgsFunction: completeRepoNames,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repoName := ""
+			if len(args) > 0 {
+				repoName = args[0]
+			}
+			if stat && nameOnly {
+				return usageErrorf(cmd, "--stat and --name-only cannot be combined")
+			}
+			if opts.Coordinates != diff.CoordinatesOrphan && opts.Coordinates != diff.CoordinatesTrunk {
+				return usageErrorf(cmd, "--coordinates must be %s or %s", diff.CoordinatesOrphan, diff.CoordinatesTrunk)
+			}
+			switch {
+			case stat:
+				opts.Format = diff.FormatStat
+			case nameOnly:
+				opts.Format = diff.FormatNameOnly
+			}
+			result, err := diff.Diff(gitClient, workDir, repoName, opts)
+			if err != nil {
+				return fmt.Errorf("failed to diff: %w", err)
+			}
+			render(result, func() { fmt.Print(result.Output) })
+			return nil
+		},
+	}, diff.Description())
+	cmd.Flags().BoolVar(&stat, "stat", false, "show a diffstat instead of the patch")
+	cmd.Flags().BoolVar(&nameOnly, "name-only", false, "show only the names of changed files")
+	cmd.Flags().StringVar(&opts.Coordinates, "coordinates", diff.CoordinatesOrphan, "show paths as on the orphan branch (orphan) or on the trunk (trunk)")
+	cmd.RegisterFlagCompletionFunc("coordinates", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{diff.CoordinatesOrphan, diff.CoordinatesTrunk}, cobra.ShellCompDirectiveNoFileComp
+	})
+	return cmd
+}
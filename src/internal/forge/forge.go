@@ -0,0 +1,128 @@
+package forge
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Supported forge providers.
+const (
+	ProviderGitHub = "github"
+	ProviderGitLab = "gitlab"
+)
+
+// Config describes how to reach a forge. It mirrors the "forge" section of gg.json.
+type Config struct {
+	Provider string `json:"provider"`           // "github" or "gitlab"
+	BaseURL  string `json:"base_url,omitempty"` // API base URL; defaults to the public instance
+	Project  string `json:"project"`            // "owner/repo" (GitHub) or "group/project" (GitLab)
+	Remote   string `json:"remote,omitempty"`   // Git remote to push to; defaults to "origin"
+	TokenEnv string `json:"token_env,omitempty"`
+}
+
+// PullRequest is the provider-agnostic description of a pull (or merge) request.
+type PullRequest struct {
+	Title  string
+	Body   string
+	Head   string // Source branch
+	Base   string // Target branch (the trunk)
+	Labels []string
+}
+
+// PullRequestResult identifies a pull request created on the forge.
+type PullRequestResult struct {
+	Number int    `json:"number"`
+	URL    string `json:"url"`
+}
+
+// Forge opens pull requests on a hosting provider.
+type Forge interface {
+	// Name returns the provider name (e.g. "github").
+	Name() string
+	// OpenPullRequest creates a pull request and applies its labels.
+	OpenPullRequest(pr PullRequest) (*PullRequestResult, error)
+}
+
+// New creates a Forge for the configured provider.
+func New(cfg Config, token string) (Forge, error) {
+	if cfg.Project == "" {
+		return nil, fmt.Errorf("forge project is not configured")
+	}
+	if token == "" {
+		return nil, fmt.Errorf("no API token for forge '%s' (set %s)", cfg.Provider, TokenEnv(cfg))
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	switch strings.ToLower(cfg.Provider) {
+	case ProviderGitHub:
+		return NewGitHub(cfg.BaseURL, cfg.Project, token, client), nil
+	case ProviderGitLab:
+		return NewGitLab(cfg.BaseURL, cfg.Project, token, client), nil
+	default:
+		return nil, fmt.Errorf("unsupported forge provider '%s' (expected %s or %s)", cfg.Provider, ProviderGitHub, ProviderGitLab)
+	}
+}
+
+// TokenEnv returns the environment variable holding the API token for the given config.
+func TokenEnv(cfg Config) string {
+	if cfg.TokenEnv != "" {
+		return cfg.TokenEnv
+	}
+	if strings.ToLower(cfg.Provider) == ProviderGitLab {
+		return "GITLAB_TOKEN"
+	}
+	return "GITHUB_TOKEN"
+}
+
+// RemoteName returns the git remote to push to, defaulting to "origin".
+func RemoteName(cfg Config) string {
+	if cfg.Remote != "" {
+		return cfg.Remote
+	}
+	return "origin"
+}
+
+// doJSON sends a JSON request and decodes a JSON response into out (if non-nil).
+func doJSON(client *http.Client, method, url string, headers map[string]string, in any, out any) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s failed: %w", method, url, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s %s returned %s: %s", method, url, resp.Status, strings.TrimSpace(string(respBody)))
+	}
+
+	if out != nil {
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return fmt.Errorf("failed to decode response from %s: %w", url, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package forge
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitHubOpenPullRequest(t *testing.T) {
+	var gotPR map[string]string
+	var gotLabels map[string][]string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/repos/acme/mono/pulls":
+			json.NewDecoder(r.Body).Decode(&gotPR)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"number": 7, "html_url": "https://example.test/acme/mono/pull/7"}`))
+		case "/repos/acme/mono/issues/7/labels":
+			json.NewDecoder(r.Body).Decode(&gotLabels)
+			w.Write([]byte(`[]`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	f, err := New(Config{Provider: "github", BaseURL: server.URL, Project: "acme/mono"}, "secret")
+	assert.NoError(t, err)
+
+	result, err := f.OpenPullRequest(PullRequest{
+		Title:  "[service-a] Integrate",
+		Body:   "body",
+		Head:   "gg/merge-prep/service-a/1",
+		Base:   "main",
+		Labels: []string{"backend"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 7, result.Number)
+	assert.Equal(t, "https://example.test/acme/mono/pull/7", result.URL)
+	assert.Equal(t, "gg/merge-prep/service-a/1", gotPR["head"])
+	assert.Equal(t, "main", gotPR["base"])
+	assert.Equal(t, []string{"backend"}, gotLabels["labels"])
+}
+
+func TestGitLabOpenPullRequest(t *testing.T) {
+	var gotMR map[string]string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret", r.Header.Get("PRIVATE-TOKEN"))
+		assert.Equal(t, "/projects/acme%2Fmono/merge_requests", r.URL.EscapedPath())
+		json.NewDecoder(r.Body).Decode(&gotMR)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"iid": 3, "web_url": "https://example.test/acme/mono/-/merge_requests/3"}`))
+	}))
+	defer server.Close()
+
+	f, err := New(Config{Provider: "gitlab", BaseURL: server.URL, Project: "acme/mono"}, "secret")
+	assert.NoError(t, err)
+
+	result, err := f.OpenPullRequest(PullRequest{
+		Title:  "[service-a] Integrate",
+		Head:   "gg/merge-prep/service-a/1",
+		Base:   "main",
+		Labels: []string{"backend", "go"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, result.Number)
+	assert.Equal(t, "main", gotMR["target_branch"])
+	assert.Equal(t, "backend,go", gotMR["labels"])
+}
+
+func TestNewValidation(t *testing.T) {
+	_, err := New(Config{Provider: "github", Project: "acme/mono"}, "")
+	assert.ErrorContains(t, err, "GITHUB_TOKEN")
+
+	_, err = New(Config{Provider: "bitbucket", Project: "acme/mono"}, "secret")
+	assert.ErrorContains(t, err, "unsupported forge provider")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"message": "Validation Failed"}`))
+	}))
+	defer server.Close()
+
+	f, _ := New(Config{Provider: "github", BaseURL: server.URL, Project: "acme/mono"}, "secret")
+	_, err = f.OpenPullRequest(PullRequest{Title: "t", Head: "h", Base: "main"})
+	assert.ErrorContains(t, err, "Validation Failed")
+}
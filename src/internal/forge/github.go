@@ -0,0 +1,72 @@
+package forge
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const defaultGitHubBaseURL = "https://api.github.com"
+
+// GitHub opens pull requests through the GitHub REST API.
+type GitHub struct {
+	baseURL string
+	repo    string // owner/repo
+	token   string
+	client  *http.Client
+}
+
+// NewGitHub creates a GitHub forge. An empty baseURL targets api.github.com.
+func NewGitHub(baseURL, repo, token string, client *http.Client) *GitHub {
+	if baseURL == "" {
+		baseURL = defaultGitHubBaseURL
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &GitHub{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		repo:    strings.Trim(repo, "/"),
+		token:   token,
+		client:  client,
+	}
+}
+
+// Name returns "github".
+func (g *GitHub) Name() string {
+	return ProviderGitHub
+}
+
+// OpenPullRequest creates the pull request, then attaches labels via the issues API.
+func (g *GitHub) OpenPullRequest(pr PullRequest) (*PullRequestResult, error) {
+	headers := map[string]string{
+		"Authorization": "Bearer " + g.token,
+		"Accept":        "application/vnd.github+json",
+	}
+
+	request := map[string]string{
+		"title": pr.Title,
+		"body":  pr.Body,
+		"head":  pr.Head,
+		"base":  pr.Base,
+	}
+	var response struct {
+		Number  int    `json:"number"`
+		HTMLURL string `json:"html_url"`
+	}
+	url := fmt.Sprintf("%s/repos/%s/pulls", g.baseURL, g.repo)
+	if err := doJSON(g.client, http.MethodPost, url, headers, request, &response); err != nil {
+		return nil, fmt.Errorf("failed to create GitHub pull request: %w", err)
+	}
+
+	result := &PullRequestResult{Number: response.Number, URL: response.HTMLURL}
+
+	if len(pr.Labels) > 0 {
+		labelsURL := fmt.Sprintf("%s/repos/%s/issues/%d/labels", g.baseURL, g.repo, response.Number)
+		if err := doJSON(g.client, http.MethodPost, labelsURL, headers, map[string][]string{"labels": pr.Labels}, nil); err != nil {
+			return result, fmt.Errorf("pull request #%d created but labels could not be applied: %w", response.Number, err)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,67 @@
+package forge
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const defaultGitLabBaseURL = "https://gitlab.com/api/v4"
+
+// GitLab opens merge requests through the GitLab REST API.
+type GitLab struct {
+	baseURL string
+	project string // group/project
+	token   string
+	client  *http.Client
+}
+
+// NewGitLab creates a GitLab forge. An empty baseURL targets gitlab.com.
+func NewGitLab(baseURL, project, token string, client *http.Client) *GitLab {
+	if baseURL == "" {
+		baseURL = defaultGitLabBaseURL
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &GitLab{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		project: strings.Trim(project, "/"),
+		token:   token,
+		client:  client,
+	}
+}
+
+// Name returns "gitlab".
+func (g *GitLab) Name() string {
+	return ProviderGitLab
+}
+
+// OpenPullRequest creates a merge request. GitLab accepts labels in the same call.
+func (g *GitLab) OpenPullRequest(pr PullRequest) (*PullRequestResult, error) {
+	headers := map[string]string{
+		"PRIVATE-TOKEN": g.token,
+	}
+
+	request := map[string]string{
+		"title":         pr.Title,
+		"description":   pr.Body,
+		"source_branch": pr.Head,
+		"target_branch": pr.Base,
+	}
+	if len(pr.Labels) > 0 {
+		request["labels"] = strings.Join(pr.Labels, ",")
+	}
+
+	var response struct {
+		IID    int    `json:"iid"`
+		WebURL string `json:"web_url"`
+	}
+	endpoint := fmt.Sprintf("%s/projects/%s/merge_requests", g.baseURL, url.PathEscape(g.project))
+	if err := doJSON(g.client, http.MethodPost, endpoint, headers, request, &response); err != nil {
+		return nil, fmt.Errorf("failed to create GitLab merge request: %w", err)
+	}
+
+	return &PullRequestResult{Number: response.IID, URL: response.WebURL}, nil
+}
@@ -0,0 +1,81 @@
+package affected
+
+import (
+	"fmt"
+	"strings"
+
+	gitUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/git"
+	groveUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/grove"
+)
+
+// Repo is a registered repository (or shared path) with the files changed in it.
+type Repo struct {
+	Name  string   `json:"name"`
+	Path  string   `json:"path"`
+	Files []string `json:"files"`
+}
+
+// Result lists what changed between two revisions.
+type Result struct {
+	Base    string   `json:"base"`
+	Head    string   `json:"head"`
+	Repos   []Repo   `json:"repos"`
+	Shared  []Repo   `json:"shared"`
+	Root    []string `json:"root"`    // files outside every repository and shared path
+	Neutral []string `json:"neutral"` // root files declared neutral (neutral_paths)
+}
+
+// Names returns the affected repository names in lexical order.
+func (r *Result) Names() []string {
+	names := make([]string, 0, len(r.Repos))
+	for _, repo := range r.Repos {
+		names = append(names, repo.Name)
+	}
+	return names
+}
+
+// Description returns a description of the affected repositories report.
+func Description() string {
+	return "Affected: Lists the repositories changed between two revisions (for CI matrices).\n" +
+		"- Compares head with its merge base with base, like a pull request diff\n" +
+		"- Maps files to repositories with the same rules as the pre-commit hook, using head's gg.json\n" +
+		"- Reports shared paths and root files separately"
+}
+
+// ParseRange splits "<base>..<head>" (or "<base>...<head>") into its revisions.
+func ParseRange(rangeSpec string) (base string, head string, err error) {
+	base, head, ok := strings.Cut(rangeSpec, "..")
+	head = strings.TrimPrefix(head, ".")
+	if !ok || base == "" || head == "" {
+		return "", "", fmt.Errorf("invalid range %q: expected <base>..<head>", rangeSpec)
+	}
+	return base, head, nil
+}
+
+// Affected attributes the files changed on head since its merge base with base.
+func Affected(git gitUtil.GitClient, ggRepoPath string, base string, head string) (*Result, error) {
+	config, err := groveUtil.LoadConfigFromGitRef(git, ggRepoPath, head)
+	if err != nil {
+		return nil, err
+	}
+	mergeBase, err := git.MergeBase(ggRepoPath, base, head)
+	if err != nil {
+		return nil, err
+	}
+	files, err := git.DiffNames(ggRepoPath, mergeBase, head)
+	if err != nil {
+		return nil, err
+	}
+
+	attribution := groveUtil.AttributeFiles(config, files)
+	result := &Result{Base: base, Head: head, Repos: []Repo{}, Shared: []Repo{}, Root: []string{}, Neutral: []string{}}
+	for _, name := range attribution.RepoNames() {
+		result.Repos = append(result.Repos, Repo{Name: name, Path: config.Repositories[name].Path, Files: attribution.Repos[name]})
+	}
+	for _, name := range attribution.SharedNames() {
+		result.Shared = append(result.Shared, Repo{Name: name, Path: config.SharedPaths[name].Path, Files: attribution.Shared[name]})
+	}
+	result.Root = append(result.Root, attribution.Root...)
+	result.Neutral = append(result.Neutral, attribution.Neutral...)
+	return result, nil
+}
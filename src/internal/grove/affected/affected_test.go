@@ -0,0 +1,85 @@
+package affected
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kuchuk-borom-debbarma/GitGrove/src/internal/testutil"
+	gitUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/git"
+	groveUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/grove"
+	"github.com/kuchuk-borom-debbarma/GitGrove/src/model"
+)
+
+func TestAffected(t *testing.T) {
+	git := gitUtil.NewExecClient()
+	dir := testutil.Workspace(t,
+		map[string]string{"services/billing/file.txt": "v1", "services/search/file.txt": "v1", "proto/file.txt": "v1"},
+		model.GGRepo{Name: "billing", Path: "services/billing"},
+		model.GGRepo{Name: "search", Path: "services/search"},
+	)
+
+	// The branch declares proto/ as a shared path: head's gg.json is used
+	exec.Command("git", "-C", dir, "checkout", "-q", "-b", "feature").Run()
+	config, _ := groveUtil.LoadConfig(dir)
+	config.SharedPaths = map[string]model.SharedPath{"proto": {Name: "proto", Path: "proto/**"}}
+	data, _ := json.MarshalIndent(config, "", "  ")
+	os.WriteFile(filepath.Join(dir, ".gg", "gg.json"), data, 0644)
+	os.WriteFile(filepath.Join(dir, "services", "billing", "file.txt"), []byte("v2"), 0644)
+	os.WriteFile(filepath.Join(dir, "proto", "file.txt"), []byte("v2"), 0644)
+	gitUtil.CommitNoVerify(dir, []string{"."}, "Change billing and proto")
+
+	// Later trunk changes are not part of the branch's diff
+	exec.Command("git", "-C", dir, "checkout", "-q", "main").Run()
+	os.WriteFile(filepath.Join(dir, "services", "search", "file.txt"), []byte("v2"), 0644)
+	gitUtil.CommitNoVerify(dir, []string{"."}, "Change search on trunk")
+
+	result, err := Affected(git, dir, "main", "feature")
+	if err != nil {
+		t.Fatalf("Affected failed: %v", err)
+	}
+	if names := strings.Join(result.Names(), ","); names != "billing" {
+		t.Errorf("expected only billing to be affected, got %s", names)
+	}
+	if result.Repos[0].Path != "services/billing" || len(result.Repos[0].Files) != 1 {
+		t.Errorf("unexpected repository entry: %+v", result.Repos[0])
+	}
+	if len(result.Shared) != 1 || result.Shared[0].Name != "proto" {
+		t.Errorf("expected the proto shared path, got %+v", result.Shared)
+	}
+	if len(result.Root) != 1 || result.Root[0] != ".gg/gg.json" {
+		t.Errorf("expected gg.json as the only root file, got %v", result.Root)
+	}
+
+	if base, head, err := ParseRange("main...feature"); err != nil || base != "main" || head != "feature" {
+		t.Errorf("ParseRange: got %q %q %v", base, head, err)
+	}
+	if _, _, err := ParseRange("main"); err == nil {
+		t.Errorf("expected an error for a range without ..")
+	}
+}
+
+func TestAffected_FakeClient(t *testing.T) {
+	git := gitUtil.NewFakeClient()
+	config := `{"repositories": {"billing": {"Name": "billing", "Path": "services/billing"}, "search": {"Name": "search", "Path": "services/search"}}}`
+	tree := map[string]string{".gg/gg.json": config, "services/billing/main.go": "v1", "services/search/main.go": "v1"}
+	base := git.AddCommit("main", "Register repos", tree)
+
+	// main moves on after the branch point; only the branch's own changes count
+	git.AddCommit("main", "Edit search", map[string]string{".gg/gg.json": config, "services/billing/main.go": "v1", "services/search/main.go": "v2"}, base)
+	git.AddCommit("feature", "Edit billing", map[string]string{".gg/gg.json": config, "services/billing/main.go": "v2", "services/search/main.go": "v1", "README.md": "docs"}, base)
+
+	result, err := Affected(git, "/workspace", "main", "feature")
+	if err != nil {
+		t.Fatalf("Affected failed: %v", err)
+	}
+	if names := result.Names(); len(names) != 1 || names[0] != "billing" {
+		t.Errorf("expected only billing, got %v", names)
+	}
+	if len(result.Root) != 1 || result.Root[0] != "README.md" {
+		t.Errorf("expected README.md as a root change, got %v", result.Root)
+	}
+}
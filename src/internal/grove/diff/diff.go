@@ -0,0 +1,128 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+
+	gitUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/git"
+	groveUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/grove"
+)
+
+// Coordinate systems for the paths in the diff.
+const (
+	CoordinatesOrphan = "orphan" // relative to the repository root, as on the orphan branch (default)
+	CoordinatesTrunk  = "trunk"  // prefixed with the repository path, as on the trunk
+)
+
+// Output formats.
+const (
+	FormatPatch    = "patch" // full patch (default)
+	FormatStat     = "stat"
+	FormatNameOnly = "name-only"
+)
+
+// Options control how the diff is shown.
+type Options struct {
+	Coordinates string
+	Format      string
+}
+
+// Result is the difference between the trunk's copy of a repository and its orphan branch tip.
+type Result struct {
+	Repo         string   `json:"repo"`
+	Trunk        string   `json:"trunk"`
+	OrphanBranch string   `json:"orphan_branch"`
+	Path         string   `json:"path"`
+	Coordinates  string   `json:"coordinates"`
+	Files        []string `json:"files"`  // changed paths in the chosen coordinates
+	Output       string   `json:"output"` // git diff output in the chosen format
+}
+
+// Description returns a description of the orphan/trunk diff.
+func Description() string {
+	return "Diff: Shows what integrating a repository would change on the trunk.\n" +
+		"- Compares the trunk's tree at the repository path with the orphan branch tip\n" +
+		"- Paths relative to the repository (--coordinates orphan) or to the monorepo (--coordinates trunk)\n" +
+		"- Full patch, --stat or --name-only; works from the trunk or the orphan branch"
+}
+
+// Diff compares the trunk's copy of repoName with its orphan branch tip (trunk is the old side).
+// Empty repoName is inferred from context.
+func Diff(git gitUtil.GitClient, ggRepoPath string, repoName string, opts Options) (*Result, error) {
+	if opts.Coordinates == "" {
+		opts.Coordinates = CoordinatesOrphan
+	}
+	if opts.Format == "" {
+		opts.Format = FormatPatch
+	}
+	if opts.Coordinates != CoordinatesOrphan && opts.Coordinates != CoordinatesTrunk {
+		return nil, fmt.Errorf("unknown coordinates '%s' (expected %s or %s)", opts.Coordinates, CoordinatesOrphan, CoordinatesTrunk)
+	}
+	if opts.Format != FormatPatch && opts.Format != FormatStat && opts.Format != FormatNameOnly {
+		return nil, fmt.Errorf("unknown format '%s' (expected %s, %s or %s)", opts.Format, FormatPatch, FormatStat, FormatNameOnly)
+	}
+
+	trunk, contextRepo := groveUtil.ResolveRepoContext(git, ggRepoPath)
+	if trunk == "" {
+		trunk, _ = git.CurrentBranch(ggRepoPath)
+	}
+	if repoName == "" {
+		repoName = contextRepo
+	}
+	if repoName == "" {
+		return nil, fmt.Errorf("repository name is required when there is no repository context")
+	}
+	config, err := groveUtil.LoadConfigFromGitRef(git, ggRepoPath, trunk)
+	if err != nil {
+		return nil, err
+	}
+	repo, exists := config.Repositories[repoName]
+	if !exists {
+		return nil, &groveUtil.RepoNotRegisteredError{Name: repoName}
+	}
+
+	result := &Result{
+		Repo:         repoName,
+		Trunk:        trunk,
+		OrphanBranch: fmt.Sprintf("gg/%s/%s", trunk, repoName),
+		Path:         strings.TrimSuffix(repo.Path, "/"),
+		Coordinates:  opts.Coordinates,
+	}
+	orphanTip, err := git.RevParse(ggRepoPath, result.OrphanBranch)
+	if err != nil {
+		return nil, fmt.Errorf("orphan branch %s not found: %w", result.OrphanBranch, err)
+	}
+
+	// .gg/trunk is an orphan-only artifact and never lands on the trunk
+	var from, to, exclude string
+	if opts.Coordinates == CoordinatesTrunk {
+		from = trunk
+		if to, err = git.GraftTree(ggRepoPath, trunk, result.Path, orphanTip); err != nil {
+			return nil, err
+		}
+		exclude = ":(exclude)" + result.Path + "/.gg/trunk"
+	} else {
+		from = gitUtil.EmptyTree
+		if tree, err := git.RevParse(ggRepoPath, trunk+":"+result.Path); err == nil {
+			from = tree
+		}
+		to = orphanTip
+		exclude = ":(exclude).gg/trunk"
+	}
+
+	names, err := git.Diff(ggRepoPath, "--name-only", "--no-renames", from, to, "--", ".", exclude)
+	if err != nil {
+		return nil, err
+	}
+	result.Files = []string{}
+	for _, name := range strings.Split(names, "\n") {
+		if name != "" {
+			result.Files = append(result.Files, name)
+		}
+	}
+
+	if result.Output, err = git.Diff(ggRepoPath, "--"+opts.Format, from, to, "--", ".", exclude); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,78 @@
+package diff
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kuchuk-borom-debbarma/GitGrove/src/internal/testutil"
+	gitUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/git"
+	"github.com/kuchuk-borom-debbarma/GitGrove/src/model"
+)
+
+func TestDiff(t *testing.T) {
+	git := gitUtil.NewExecClient()
+	dir := testutil.Workspace(t,
+		map[string]string{"services/api/a.txt": "v1\n", "services/api/b.txt": "b\n"},
+		model.GGRepo{Name: "api", Path: "services/api"},
+	)
+
+	// Freshly registered: nothing to integrate
+	result, err := Diff(git, dir, "api", Options{})
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	if len(result.Files) != 0 || result.Output != "" {
+		t.Errorf("expected no difference after registration, got %v:\n%s", result.Files, result.Output)
+	}
+
+	// Modify, delete and add on the orphan branch; the diff is taken from its context
+	gitUtil.Checkout(dir, "gg/main/api")
+	os.WriteFile(filepath.Join(dir, "a.txt"), []byte("v2\n"), 0644)
+	os.Remove(filepath.Join(dir, "b.txt"))
+	os.WriteFile(filepath.Join(dir, "c.txt"), []byte("c\n"), 0644)
+	gitUtil.CommitNoVerify(dir, []string{"."}, "Change api")
+
+	result, err = Diff(git, dir, "", Options{})
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	if files := strings.Join(result.Files, ","); files != "a.txt,b.txt,c.txt" {
+		t.Errorf("expected orphan paths, got %s", files)
+	}
+	if !strings.Contains(result.Output, "diff --git a/a.txt b/a.txt") || !strings.Contains(result.Output, "-v1\n+v2") {
+		t.Errorf("unexpected patch:\n%s", result.Output)
+	}
+	if strings.Contains(result.Output, ".gg/trunk") {
+		t.Errorf("orphan marker must not be part of the diff:\n%s", result.Output)
+	}
+
+	// Trunk coordinates, from the trunk
+	gitUtil.Checkout(dir, "main")
+	result, err = Diff(git, dir, "api", Options{Coordinates: CoordinatesTrunk, Format: FormatNameOnly})
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	if files := strings.Join(result.Files, ","); files != "services/api/a.txt,services/api/b.txt,services/api/c.txt" {
+		t.Errorf("expected trunk paths, got %s", files)
+	}
+	if result.Output != "services/api/a.txt\nservices/api/b.txt\nservices/api/c.txt\n" {
+		t.Errorf("unexpected name-only output: %q", result.Output)
+	}
+
+	result, err = Diff(git, dir, "api", Options{Format: FormatStat})
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	if !strings.Contains(result.Output, "3 files changed") {
+		t.Errorf("unexpected stat output:\n%s", result.Output)
+	}
+
+	if _, err := Diff(git, dir, "unknown", Options{}); err == nil {
+		t.Errorf("expected an error for an unregistered repository")
+	}
+	if _, err := Diff(git, dir, "", Options{}); err == nil {
+		t.Errorf("expected an error without repository context")
+	}
+}
@@ -0,0 +1,253 @@
+package hooks
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	gitUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/git"
+	groveUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/grove"
+	"github.com/kuchuk-borom-debbarma/GitGrove/src/model"
+)
+
+// DefaultConventionalTypes are the Conventional Commits types accepted when CommitRules.Types is empty.
+var DefaultConventionalTypes = []string{"feat", "fix", "docs", "style", "refactor", "perf", "test", "build", "ci", "chore", "revert"}
+
+var (
+	repoPrefixPattern   = regexp.MustCompile(`^\[([^\]\s]+)\]\s*`)
+	conventionalPattern = regexp.MustCompile(`^([a-zA-Z]+)(?:\(([^()]*)\))?(!)?: (\S.*)$`)
+)
+
+// scissorsLine marks the start of the diff appended by `git commit --verbose`.
+const scissorsLine = "# ------------------------ >8 ------------------------"
+
+// CommitMsg validates the final commit message written to msgFile.
+// It rejects a [repo] prefix that does not match the staged files and, for commits that belong to
+// a single repository, enforces that repository's CommitRules from gg.json.
+func CommitMsg(git gitUtil.GitClient, msgFile string) error {
+	root, err := git.RepoRoot()
+	if err != nil {
+		root, _ = os.Getwd()
+	}
+
+	config, err := loadHookConfig(git, root)
+	if err != nil {
+		return err
+	}
+	if config == nil {
+		return nil
+	}
+
+	msgBytes, err := os.ReadFile(msgFile)
+	if err != nil {
+		return fmt.Errorf("failed to read commit message file: %w", err)
+	}
+	message := cleanCommitMessage(string(msgBytes))
+	if message == "" {
+		// git aborts empty commits on its own
+		return nil
+	}
+
+	owner, ownerKnown, err := commitOwner(git, root, config)
+	if err != nil {
+		return err
+	}
+
+	var violations []string
+
+	// 1. The [repo] prefix must name the repository the commit actually belongs to
+	if ownerKnown {
+		subject := strings.SplitN(message, "\n", 2)[0]
+		violations = append(violations, CheckRepoPrefix(config, owner, subject)...)
+	}
+
+	// 2. Per-repository conventions
+	if owner != "" && !skipCommitRules(git, root, message) {
+		if repo, ok := config.Repositories[owner]; ok {
+			violations = append(violations, CheckCommitMessage(repo.CommitRules, message)...)
+		}
+	}
+
+	if len(violations) > 0 {
+		return fmt.Errorf("commit message rejected for repository '%s':\n  - %s", displayOwner(owner), strings.Join(violations, "\n  - "))
+	}
+	return nil
+}
+
+// CheckRepoPrefix checks that a [repo] prefix in subject names owner, the repository the commit's
+// files belong to (empty for root or mixed commits). Prefixes naming unregistered repositories are ignored.
+func CheckRepoPrefix(config *groveUtil.GGConfig, owner string, subject string) []string {
+	prefixRepo := RepoPrefix(subject)
+	if prefixRepo == "" || prefixRepo == owner {
+		return nil
+	}
+	if _, registered := config.Repositories[prefixRepo]; !registered {
+		return nil
+	}
+	if owner == "" {
+		return []string{fmt.Sprintf("message is prefixed with [%s] but the committed files do not belong to repository '%s'", prefixRepo, prefixRepo)}
+	}
+	return []string{fmt.Sprintf("message is prefixed with [%s] but the committed files belong to repository '%s'", prefixRepo, owner)}
+}
+
+// RepoPrefix returns the repository named by a leading "[repo]" in subject, or an empty string.
+func RepoPrefix(subject string) string {
+	if match := repoPrefixPattern.FindStringSubmatch(subject); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+// CheckCommitMessage checks a commit message against rules and returns one entry per violation.
+// A leading "[repo] " prefix is ignored. A nil rules value accepts every message.
+func CheckCommitMessage(rules *model.CommitRules, message string) []string {
+	if rules == nil {
+		return nil
+	}
+
+	subject := strings.SplitN(message, "\n", 2)[0]
+	subject = repoPrefixPattern.ReplaceAllString(subject, "")
+
+	var violations []string
+
+	if rules.MaxSubjectLength > 0 {
+		if length := utf8.RuneCountInString(subject); length > rules.MaxSubjectLength {
+			violations = append(violations, fmt.Sprintf("subject is %d characters long (maximum %d)", length, rules.MaxSubjectLength))
+		}
+	}
+
+	if rules.Conventional {
+		violations = append(violations, checkConventional(rules, subject)...)
+	}
+
+	if rules.TicketPattern != "" {
+		ticket, err := regexp.Compile(rules.TicketPattern)
+		if err != nil {
+			violations = append(violations, fmt.Sprintf("invalid ticket_pattern %q in gg.json: %v", rules.TicketPattern, err))
+		} else if !ticket.MatchString(message) {
+			violations = append(violations, fmt.Sprintf("message does not reference a ticket matching %q", rules.TicketPattern))
+		}
+	}
+
+	return violations
+}
+
+func checkConventional(rules *model.CommitRules, subject string) []string {
+	match := conventionalPattern.FindStringSubmatch(subject)
+	if match == nil {
+		return []string{fmt.Sprintf("subject %q is not a Conventional Commit (expected \"type(scope): description\")", subject)}
+	}
+	commitType, scope := match[1], match[2]
+
+	var violations []string
+	types := rules.Types
+	if len(types) == 0 {
+		types = DefaultConventionalTypes
+	}
+	if !contains(types, commitType) {
+		violations = append(violations, fmt.Sprintf("type '%s' is not allowed (allowed: %s)", commitType, strings.Join(types, ", ")))
+	}
+
+	if scope == "" {
+		if rules.RequireScope {
+			violations = append(violations, "a scope is required, e.g. \"feat(api): ...\"")
+		}
+	} else if len(rules.Scopes) > 0 && !contains(rules.Scopes, scope) {
+		violations = append(violations, fmt.Sprintf("scope '%s' is not allowed (allowed: %s)", scope, strings.Join(rules.Scopes, ", ")))
+	}
+	return violations
+}
+
+// commitOwner determines which repository the commit being created belongs to.
+// owner is empty for root or mixed commits. ownerKnown is false when nothing is staged
+// (e.g. a message-only amend), in which case the prefix cannot be verified.
+func commitOwner(git gitUtil.GitClient, root string, config *groveUtil.GGConfig) (owner string, ownerKnown bool, err error) {
+	if !groveUtil.HasTrunkTree(root) {
+		// Sticky context: everything committed belongs to the repo we checked out
+		if stickyRepo, _ := groveUtil.GetContextRepo(git, root); stickyRepo != "" {
+			if _, exists := config.Repositories[stickyRepo]; exists {
+				return stickyRepo, true, nil
+			}
+		}
+
+		// Orphan branch: paths are relative to the repository, not the trunk
+		if currentBranch, err := git.CurrentBranch(root); err == nil {
+			if _, repoName, ok := groveUtil.ParseOrphanBranch(currentBranch); ok {
+				return repoName, true, nil
+			}
+		}
+	}
+
+	stagedFiles, err := git.GetStagedFiles(root)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get staged files: %w", err)
+	}
+	if len(stagedFiles) == 0 {
+		return "", false, nil
+	}
+
+	attribution := groveUtil.AttributeFiles(config, stagedFiles)
+	if len(attribution.Repos) == 1 && !attribution.AffectsRoot() {
+		return attribution.RepoNames()[0], true, nil
+	}
+	return "", true, nil
+}
+
+// skipCommitRules reports whether message was generated by git or GitGrove rather than written
+// by hand: merges, fixup/squash commits, reverts, and integration commits.
+func skipCommitRules(git gitUtil.GitClient, root string, message string) bool {
+	if gitDir, err := git.GitDir(root); err == nil {
+		if _, err := os.Stat(filepath.Join(gitDir, "MERGE_HEAD")); err == nil {
+			return true
+		}
+	}
+
+	return IsGeneratedMessage(message)
+}
+
+// IsGeneratedMessage reports whether message was written by git or GitGrove: fixup/squash commits,
+// reverts, merges, and integration commits carrying a GG-Orphan-Commit trailer.
+func IsGeneratedMessage(message string) bool {
+	subject := repoPrefixPattern.ReplaceAllString(strings.SplitN(message, "\n", 2)[0], "")
+	for _, generated := range []string{"fixup! ", "squash! ", "amend! ", "Revert \"", "Merge "} {
+		if strings.HasPrefix(subject, generated) {
+			return true
+		}
+	}
+
+	return strings.Contains(message, "\n"+groveUtil.OrphanCommitTrailer+":")
+}
+
+// cleanCommitMessage strips comment lines, the verbose diff, and surrounding blank lines the way git does.
+func cleanCommitMessage(raw string) string {
+	if i := strings.Index(raw, scissorsLine); i >= 0 {
+		raw = raw[:i]
+	}
+	var lines []string
+	for _, line := range strings.Split(raw, "\n") {
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, strings.TrimRight(line, " \t\r"))
+	}
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
+
+func displayOwner(owner string) string {
+	if owner == "" {
+		return "<root>"
+	}
+	return owner
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
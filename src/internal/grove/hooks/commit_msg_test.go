@@ -0,0 +1,124 @@
+package hooks
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	gitUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/git"
+	"github.com/kuchuk-borom-debbarma/GitGrove/src/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommitMsg(t *testing.T) {
+	git := gitUtil.NewExecClient()
+	tmpDir, err := os.MkdirTemp("", "gitgrove-test-commit-msg-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	exec.Command("git", "init").Run()
+	exec.Command("git", "config", "user.email", "you@example.com").Run()
+	exec.Command("git", "config", "user.name", "Your Name").Run()
+
+	os.MkdirAll(filepath.Join(tmpDir, ".gg"), 0755)
+	configJSON := `{
+  "repositories": {
+    "repoA": {"Name": "repoA", "Path": "services/repoA",
+      "CommitRules": {"conventional": true, "scopes": ["api", "db"], "ticket_pattern": "[A-Z]+-[0-9]+", "max_subject_length": 50}},
+    "repoB": {"Name": "repoB", "Path": "services/repoB"}
+  },
+  "repo_aware_context_message": true
+}`
+	os.WriteFile(filepath.Join(tmpDir, ".gg", "gg.json"), []byte(configJSON), 0644)
+	exec.Command("git", "add", ".").Run()
+	exec.Command("git", "commit", "-m", "init gg.json").Run()
+
+	os.MkdirAll(filepath.Join(tmpDir, "services", "repoA"), 0755)
+	os.MkdirAll(filepath.Join(tmpDir, "services", "repoB"), 0755)
+	msgFile := filepath.Join(tmpDir, ".git", "COMMIT_EDITMSG")
+
+	// Case 1: repoA commit following the rules -> passes
+	os.WriteFile(filepath.Join(tmpDir, "services", "repoA", "a.txt"), []byte("a"), 0644)
+	exec.Command("git", "add", ".").Run()
+	os.WriteFile(msgFile, []byte("[repoA] feat(api): add endpoint\n\nRefs ABC-123\n# Please enter the commit message\n"), 0644)
+	assert.NoError(t, CommitMsg(git, msgFile))
+
+	// Case 2: repoA commit breaking every rule -> all violations reported
+	os.WriteFile(msgFile, []byte("[repoA] added a rather long subject line that goes past the limit"), 0644)
+	err = CommitMsg(git, msgFile)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "not a Conventional Commit")
+		assert.Contains(t, err.Error(), "maximum 50")
+		assert.Contains(t, err.Error(), "ticket")
+	}
+
+	// Case 3: disallowed type and scope
+	os.WriteFile(msgFile, []byte("[repoA] wip(ui): tweak ABC-1"), 0644)
+	err = CommitMsg(git, msgFile)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "type 'wip'")
+		assert.Contains(t, err.Error(), "scope 'ui'")
+	}
+
+	// Case 4: prefix typed for the wrong repo -> rejected
+	os.WriteFile(msgFile, []byte("[repoB] feat(api): add endpoint ABC-1"), 0644)
+	err = CommitMsg(git, msgFile)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "belong to repository 'repoA'")
+	}
+	exec.Command("git", "commit", "--no-verify", "-m", "repoA").Run()
+
+	// Case 5: repoB has no rules
+	os.WriteFile(filepath.Join(tmpDir, "services", "repoB", "b.txt"), []byte("b"), 0644)
+	exec.Command("git", "add", ".").Run()
+	os.WriteFile(msgFile, []byte("[repoB] anything goes"), 0644)
+	assert.NoError(t, CommitMsg(git, msgFile))
+	exec.Command("git", "commit", "--no-verify", "-m", "repoB").Run()
+
+	// Case 6: root-only commit carrying a repo prefix -> rejected; unregistered prefixes are ignored
+	os.WriteFile(filepath.Join(tmpDir, "README.md"), []byte("readme"), 0644)
+	exec.Command("git", "add", ".").Run()
+	os.WriteFile(msgFile, []byte("[repoA] docs: readme"), 0644)
+	err = CommitMsg(git, msgFile)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "do not belong to repository 'repoA'")
+	}
+	os.WriteFile(msgFile, []byte("[WIP] readme"), 0644)
+	assert.NoError(t, CommitMsg(git, msgFile))
+	exec.Command("git", "commit", "--no-verify", "-m", "readme").Run()
+
+	// Case 7: stale sticky context on the trunk (plain `git checkout` after `gg checkout repoA`):
+	// the staged files decide, for the prepared prefix as well as the check
+	exec.Command("git", "config", "gitgrove.context.repo", "repoA").Run()
+	os.WriteFile(filepath.Join(tmpDir, "services", "repoB", "b.txt"), []byte("b2"), 0644)
+	exec.Command("git", "add", ".").Run()
+	os.WriteFile(msgFile, []byte("[repoB] fix b"), 0644)
+	assert.NoError(t, PrepareCommitMsg(git, msgFile, "message", ""))
+	content, _ := os.ReadFile(msgFile)
+	assert.Equal(t, "[repoB] fix b", string(content))
+	assert.NoError(t, CommitMsg(git, msgFile))
+
+	os.WriteFile(msgFile, []byte("fix b"), 0644)
+	assert.NoError(t, PrepareCommitMsg(git, msgFile, "message", ""))
+	content, _ = os.ReadFile(msgFile)
+	assert.Equal(t, "[repoB] fix b", string(content))
+}
+
+func TestCheckCommitMessage(t *testing.T) {
+	rules := &model.CommitRules{Conventional: true, RequireScope: true, Types: []string{"feat"}}
+
+	assert.Empty(t, CheckCommitMessage(nil, "whatever"))
+	assert.Empty(t, CheckCommitMessage(rules, "[svc] feat(core)!: breaking change"))
+
+	violations := CheckCommitMessage(rules, "fix: bug")
+	assert.Len(t, violations, 2)
+	assert.True(t, strings.Contains(strings.Join(violations, "\n"), "scope is required"))
+}
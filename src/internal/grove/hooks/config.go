@@ -0,0 +1,73 @@
+package hooks
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	gitUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/git"
+	groveUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/grove"
+)
+
+// loadHookConfig loads gg.json for a hook running in root. Outside the trunk the file is usually
+// not on disk (orphan or feature branches), so the trunk's committed copy is used instead.
+// Returns nil, nil when root is not a GitGrove workspace.
+func loadHookConfig(git gitUtil.GitClient, root string) (*groveUtil.GGConfig, error) {
+	config, err := groveUtil.LoadConfig(root)
+	if err != nil {
+		// Not a grove repo or error loading config.
+		// If working in an orphan branch (gg/<trunk>/<repoName>), config might not exist on disk,
+		// but should exist in the <trunk> branch.
+		isMissing := errors.Is(err, os.ErrNotExist)
+
+		loadedFromBranch := false
+		if isMissing {
+			// Check if we are in an orphan branch
+			currentBranch, branchErr := git.CurrentBranch(root)
+			if branchErr == nil && strings.HasPrefix(currentBranch, "gg/") {
+				parts := strings.Split(currentBranch, "/")
+				if len(parts) >= 3 {
+					// gg/<trunk>/<repoName> -> we need <trunk> (might contain slashes)
+					// Assumes loose structure: trunk is everything between gg/ and /<repoName>
+					// Or we can try to find config in potential trunk candidates.
+					// Simplest heuristic: <trunk> is everything in between.
+					trunk := strings.Join(parts[1:len(parts)-1], "/")
+
+					// Try to load from trunk
+					branchConfig, branchConfigErr := groveUtil.LoadConfigFromGitRef(git, root, trunk)
+					if branchConfigErr == nil {
+						config = branchConfig
+						loadedFromBranch = true
+					}
+				}
+			} else {
+				// Sticky Context Logic for Trunk config loading
+				// If not an orphan branch (e.g. feature branch off orphan), try to find trunk from sticky config
+				stickyTrunk, _ := groveUtil.GetContextTrunk(git, root)
+				if stickyTrunk != "" {
+					branchConfig, branchConfigErr := groveUtil.LoadConfigFromGitRef(git, root, stickyTrunk)
+					if branchConfigErr == nil {
+						config = branchConfig
+						loadedFromBranch = true
+					}
+				}
+			}
+		}
+
+		if !loadedFromBranch {
+			// If still not loaded, treat as genuine error or not initialized
+			if isMissing {
+				// Not initialized
+				return nil, nil
+			}
+			// Double check existence to be sure
+			if _, statErr := os.Stat(filepath.Join(root, ".gg", "gg.json")); os.IsNotExist(statErr) {
+				return nil, nil
+			}
+			return nil, err
+		}
+	}
+
+	return config, nil
+}
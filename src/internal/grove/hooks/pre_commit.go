@@ -1,6 +1,7 @@
 package hooks
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -8,13 +9,14 @@ import (
 
 	gitUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/git"
 	groveUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/grove"
+	"github.com/kuchuk-borom-debbarma/GitGrove/src/model"
 )
 
 // PreCommit enforces atomic commits in the GitGrove monorepo.
-func PreCommit() error {
+func PreCommit(git gitUtil.GitClient) error {
 	// 1. Check for .gg/gg.json
 	// Ensure we are at the root
-	root, err := gitUtil.RepoRoot()
+	root, err := git.RepoRoot()
 	if err != nil {
 		// Fallback specific to pre-commit: if we can't find root, we can't enforce global rules reliably?
 		// Or we assume CWD is ok?
@@ -26,19 +28,19 @@ func PreCommit() error {
 	config, err := groveUtil.LoadConfig(root)
 	if err != nil {
 		// If config load fails because file doesn't exist, we assume we are not in a context that needs enforcement
-		// This covers orphan branches and non-grove repos
-		if os.IsNotExist(err) || strings.Contains(err.Error(), "no such file") {
-			return nil
+		// This covers orphan branches and non-grove repos (only the scope lock still applies)
+		if errors.Is(err, os.ErrNotExist) {
+			return checkScopeOffTrunk(git, root)
 		}
 		// Double check existence to be sure
 		if _, statErr := os.Stat(filepath.Join(root, ".gg", "gg.json")); os.IsNotExist(statErr) {
-			return nil
+			return checkScopeOffTrunk(git, root)
 		}
 		return err
 	}
 
 	// 2. Get staged files
-	stagedFiles, err := gitUtil.GetStagedFiles(root)
+	stagedFiles, err := git.GetStagedFiles(root)
 	if err != nil {
 		return fmt.Errorf("failed to get staged files: %w", err)
 	}
@@ -48,44 +50,73 @@ func PreCommit() error {
 	}
 
 	// 3. Enforce Atomic Commit
-	affectedRepos := make(map[string]bool)
-	affectedRoot := false
-
-	for _, file := range stagedFiles {
-		matched := false
-		for _, repo := range config.Repositories {
-			// Check if file is inside repo.Path
-			// We assume repo.Path is relative to root
-			relPath, err := filepath.Rel(repo.Path, file)
-			if err == nil && !strings.HasPrefix(relPath, "..") {
-				affectedRepos[repo.Name] = true
-				matched = true
-				break
-			}
-		}
-		if !matched {
-			affectedRoot = true
-		}
+	attribution := groveUtil.AttributeFiles(config, stagedFiles)
+	if err := checkScope(git, root, config, attribution); err != nil {
+		return err
 	}
+	if err := CheckAtomicity(config, attribution); err != nil {
+		return fmt.Errorf("%w\nTip: gg split-commit -m \"<message>\" commits each repository separately", err)
+	}
+
+	// 4. Keep protected repositories read-only on the trunk
+	return checkTrunkProtection(git, root, config, attribution)
+}
+
+// CheckAtomicity applies the atomic commit rules to the files of a single commit.
+// It is shared by the hooks and gg verify.
+func CheckAtomicity(config *groveUtil.GGConfig, attribution *groveUtil.Attribution) error {
+	affectedRepos := attribution.RepoNames()
+	affectedRoot := attribution.AffectsRoot()
 
 	// Rules:
 	// 1. Cannot touch > 1 registered repo
 	if len(affectedRepos) > 1 {
-		repos := []string{}
-		for r := range affectedRepos {
-			repos = append(repos, r)
-		}
-		return fmt.Errorf("atomic commit violation: commit touches multiple registered repositories: %v", repos)
+		return fmt.Errorf("atomic commit violation: commit touches multiple registered repositories: %v", affectedRepos)
 	}
 
 	// 2. Cannot touch Repo + Root
 	if len(affectedRepos) == 1 && affectedRoot {
-		var repoName string
-		for r := range affectedRepos {
-			repoName = r
+		return fmt.Errorf("atomic commit violation: commit mixes files from repository '%s' and root files %v", affectedRepos[0], attribution.Root)
+	}
+
+	// 3. Shared paths follow their own policy
+	for _, name := range attribution.SharedNames() {
+		shared := config.SharedPaths[name]
+		switch shared.Policy {
+		case "", model.SharedPolicyStandalone:
+			if len(affectedRepos) > 0 {
+				return fmt.Errorf("atomic commit violation: shared path '%s' (%s) has policy '%s' and cannot be committed together with repository '%s'; commit it separately",
+					name, shared.Path, model.SharedPolicyStandalone, affectedRepos[0])
+			}
+		case model.SharedPolicyWithRepo:
+			// Allowed alone or with the single repository checked above
+		case model.SharedPolicyExclusive:
+			if len(affectedRepos) > 0 || affectedRoot || len(attribution.Shared) > 1 {
+				return fmt.Errorf("atomic commit violation: shared path '%s' (%s) has policy '%s' and must be committed on its own, but the commit also touches %s",
+					name, shared.Path, model.SharedPolicyExclusive, describeOthers(attribution, name))
+			}
+		default:
+			return fmt.Errorf("shared path '%s' has unknown policy '%s' in gg.json (expected %s, %s or %s)",
+				name, shared.Policy, model.SharedPolicyStandalone, model.SharedPolicyWithRepo, model.SharedPolicyExclusive)
 		}
-		return fmt.Errorf("atomic commit violation: commit mixes files from repository '%s' and root files", repoName)
 	}
 
 	return nil
 }
+
+// describeOthers lists everything in the commit apart from the given shared path, for error messages.
+func describeOthers(attribution *groveUtil.Attribution, exceptShared string) string {
+	var parts []string
+	for _, repo := range attribution.RepoNames() {
+		parts = append(parts, fmt.Sprintf("repository '%s'", repo))
+	}
+	for _, shared := range attribution.SharedNames() {
+		if shared != exceptShared {
+			parts = append(parts, fmt.Sprintf("shared path '%s'", shared))
+		}
+	}
+	if attribution.AffectsRoot() {
+		parts = append(parts, fmt.Sprintf("root files %v", attribution.Root))
+	}
+	return strings.Join(parts, ", ")
+}
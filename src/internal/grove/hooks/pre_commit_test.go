@@ -1,15 +1,21 @@
 package hooks
 
 import (
+	"encoding/json"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/kuchuk-borom-debbarma/GitGrove/src/internal/testutil"
+	gitUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/git"
 	groveUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/grove"
 	"github.com/kuchuk-borom-debbarma/GitGrove/src/model"
 )
 
 func TestPreCommit(t *testing.T) {
+	git := gitUtil.NewExecClient()
 	// Create temp dir
 	tmpDir, err := os.MkdirTemp("", "gg-test-*")
 	if err != nil {
@@ -30,7 +36,7 @@ func TestPreCommit(t *testing.T) {
 	exec.Command("git", "config", "user.name", "Your Name").Run()
 
 	// Case 1: No gg.json -> Should pass
-	if err := PreCommit(); err != nil {
+	if err := PreCommit(git); err != nil {
 		t.Errorf("expected pass when no gg.json, got error: %v", err)
 	}
 
@@ -53,7 +59,7 @@ func TestPreCommit(t *testing.T) {
 	// Case 2: Modify repoA only -> Should pass
 	os.WriteFile("services/repoA/file.txt", []byte("content"), 0644)
 	exec.Command("git", "add", "services/repoA/file.txt").Run()
-	if err := PreCommit(); err != nil {
+	if err := PreCommit(git); err != nil {
 		t.Errorf("expected pass for single repo commit, got error: %v", err)
 	}
 	exec.Command("git", "commit", "-m", "repoA commit").Run()
@@ -62,7 +68,7 @@ func TestPreCommit(t *testing.T) {
 	os.WriteFile("services/repoA/file2.txt", []byte("content"), 0644)
 	os.WriteFile("services/repoB/file.txt", []byte("content"), 0644)
 	exec.Command("git", "add", "services/repoA/file2.txt", "services/repoB/file.txt").Run()
-	if err := PreCommit(); err == nil {
+	if err := PreCommit(git); err == nil {
 		t.Error("expected fail for mixed repo commit, got nil")
 	} else {
 		t.Logf("Got expected error: %v", err)
@@ -74,9 +80,251 @@ func TestPreCommit(t *testing.T) {
 	os.WriteFile("services/repoA/file3.txt", []byte("content"), 0644)
 	os.WriteFile("README.md", []byte("content"), 0644)
 	exec.Command("git", "add", "services/repoA/file3.txt", "README.md").Run()
-	if err := PreCommit(); err == nil {
+	if err := PreCommit(git); err == nil {
 		t.Error("expected fail for repo+root commit, got nil")
 	} else {
 		t.Logf("Got expected error: %v", err)
 	}
 }
+
+func TestPreCommit_NeutralPaths(t *testing.T) {
+	git := gitUtil.NewExecClient()
+	tmpDir := t.TempDir()
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+	os.Chdir(tmpDir)
+
+	exec.Command("git", "init").Run()
+	exec.Command("git", "config", "user.email", "you@example.com").Run()
+	exec.Command("git", "config", "user.name", "Your Name").Run()
+
+	os.MkdirAll(".gg", 0755)
+	configJSON := `{
+  "repositories": {
+    "repoA": {"Name": "repoA", "Path": "services/repoA", "NeutralPaths": ["deploy/repoA-*.yaml"]},
+    "repoB": {"Name": "repoB", "Path": "services/repoB"}
+  },
+  "repo_aware_context_message": true,
+  "neutral_paths": ["go.work", ".github/workflows", "**/CODEOWNERS"]
+}`
+	os.WriteFile(".gg/gg.json", []byte(configJSON), 0644)
+	exec.Command("git", "add", ".").Run()
+	exec.Command("git", "commit", "-m", "init gg.json").Run()
+
+	os.MkdirAll("services/repoA", 0755)
+	os.MkdirAll(".github/workflows", 0755)
+	os.MkdirAll("deploy", 0755)
+
+	// Case 1: repo + globally neutral files -> passes and is still tagged
+	os.WriteFile("services/repoA/main.go", []byte("package main"), 0644)
+	os.WriteFile("go.work", []byte("go 1.22"), 0644)
+	os.WriteFile(".github/workflows/repoA.yml", []byte("on: push"), 0644)
+	os.WriteFile("CODEOWNERS", []byte("* @team"), 0644)
+	exec.Command("git", "add", ".").Run()
+	if err := PreCommit(git); err != nil {
+		t.Errorf("expected neutral root files to be allowed, got error: %v", err)
+	}
+	msgFile := filepath.Join(".git", "COMMIT_EDITMSG")
+	os.WriteFile(msgFile, []byte("bump"), 0644)
+	if err := PrepareCommitMsg(git, msgFile, "", ""); err != nil {
+		t.Fatalf("PrepareCommitMsg failed: %v", err)
+	}
+	if content, _ := os.ReadFile(msgFile); string(content) != "[repoA] bump" {
+		t.Errorf("expected commit to be tagged [repoA], got %q", content)
+	}
+	exec.Command("git", "commit", "--no-verify", "-m", "repoA").Run()
+
+	// Case 2: per-repo neutral path accompanies its own repo only
+	os.WriteFile("services/repoA/main.go", []byte("package main\n"), 0644)
+	os.WriteFile("deploy/repoA-prod.yaml", []byte("replicas: 2"), 0644)
+	exec.Command("git", "add", ".").Run()
+	if err := PreCommit(git); err != nil {
+		t.Errorf("expected repo neutral path to be allowed, got error: %v", err)
+	}
+	exec.Command("git", "reset").Run()
+
+	os.MkdirAll("services/repoB", 0755)
+	os.WriteFile("services/repoB/main.go", []byte("package main"), 0644)
+	exec.Command("git", "add", "services/repoB/main.go", "deploy/repoA-prod.yaml").Run()
+	if err := PreCommit(git); err == nil {
+		t.Error("expected repoA's neutral path to count as root for repoB")
+	}
+	exec.Command("git", "reset").Run()
+
+	// Case 3: other root files are still rejected
+	os.WriteFile("Makefile", []byte("all:"), 0644)
+	exec.Command("git", "add", "services/repoB/main.go", "Makefile").Run()
+	if err := PreCommit(git); err == nil {
+		t.Error("expected fail for repo+root commit, got nil")
+	}
+}
+
+func TestPreCommit_SharedPaths(t *testing.T) {
+	git := gitUtil.NewExecClient()
+	tmpDir := t.TempDir()
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+	os.Chdir(tmpDir)
+
+	exec.Command("git", "init").Run()
+	exec.Command("git", "config", "user.email", "you@example.com").Run()
+	exec.Command("git", "config", "user.name", "Your Name").Run()
+
+	os.MkdirAll(".gg", 0755)
+	configJSON := `{
+  "repositories": {
+    "repoA": {"Name": "repoA", "Path": "services/repoA"}
+  },
+  "shared_paths": {
+    "proto":   {"Name": "proto", "Path": "proto", "Policy": "with-repo"},
+    "scripts": {"Name": "scripts", "Path": "scripts"},
+    "vendor":  {"Name": "vendor", "Path": "third_party", "Policy": "exclusive"}
+  }
+}`
+	os.WriteFile(".gg/gg.json", []byte(configJSON), 0644)
+	exec.Command("git", "add", ".").Run()
+	exec.Command("git", "commit", "-m", "init gg.json").Run()
+
+	for _, dir := range []string{"services/repoA", "proto", "scripts", "third_party"} {
+		os.MkdirAll(dir, 0755)
+	}
+	os.WriteFile("services/repoA/main.go", []byte("package main"), 0644)
+	os.WriteFile("proto/api.proto", []byte("syntax = \"proto3\";"), 0644)
+	os.WriteFile("scripts/build.sh", []byte("#!/bin/sh"), 0644)
+	os.WriteFile("third_party/lib.go", []byte("package lib"), 0644)
+
+	cases := []struct {
+		name    string
+		files   []string
+		wantErr string
+	}{
+		{"with-repo alone", []string{"proto/api.proto"}, ""},
+		{"with-repo accompanies repo", []string{"services/repoA/main.go", "proto/api.proto"}, ""},
+		{"standalone alone", []string{"scripts/build.sh"}, ""},
+		{"standalone with repo", []string{"services/repoA/main.go", "scripts/build.sh"}, "policy 'standalone'"},
+		{"exclusive alone", []string{"third_party/lib.go"}, ""},
+		{"exclusive with shared", []string{"third_party/lib.go", "proto/api.proto"}, "policy 'exclusive'"},
+	}
+	for _, tc := range cases {
+		exec.Command("git", "reset").Run()
+		exec.Command("git", append([]string{"add"}, tc.files...)...).Run()
+		err := PreCommit(git)
+		if tc.wantErr == "" && err != nil {
+			t.Errorf("%s: expected pass, got error: %v", tc.name, err)
+		}
+		if tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)) {
+			t.Errorf("%s: expected error containing %q, got %v", tc.name, tc.wantErr, err)
+		}
+	}
+}
+
+func TestPreCommit_ProtectTrunk(t *testing.T) {
+	git := gitUtil.NewExecClient()
+	optOut := false
+	dir := testutil.Workspace(t,
+		map[string]string{"services/repoA/a.txt": "a", "services/repoB/b.txt": "b"},
+		model.GGRepo{Name: "repoA", Path: "services/repoA"},
+		model.GGRepo{Name: "repoB", Path: "services/repoB", ProtectTrunk: &optOut},
+	)
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+	os.Chdir(dir)
+
+	// Turn protection on globally
+	config, _ := groveUtil.LoadConfig(dir)
+	config.ProtectTrunk = true
+	data, _ := json.MarshalIndent(config, "", "  ")
+	os.WriteFile(filepath.Join(dir, ".gg", "gg.json"), data, 0644)
+	gitUtil.CommitNoVerify(dir, []string{".gg/gg.json"}, "Protect trunk")
+
+	// Case 1: protected repo edited on trunk -> rejected with a hint
+	os.WriteFile(filepath.Join(dir, "services", "repoA", "a.txt"), []byte("a2"), 0644)
+	exec.Command("git", "add", ".").Run()
+	err := PreCommit(git)
+	if err == nil || !strings.Contains(err.Error(), "gg checkout repoA") {
+		t.Errorf("expected trunk protection error, got: %v", err)
+	}
+
+	// Case 2: explicit override -> allowed and recorded as a trailer
+	t.Setenv(TrunkOverrideEnv, "hotfix for incident 42")
+	if err := PreCommit(git); err != nil {
+		t.Errorf("expected override to pass, got: %v", err)
+	}
+	msgFile := filepath.Join(dir, ".git", "COMMIT_EDITMSG")
+	os.WriteFile(msgFile, []byte("Hotfix\n"), 0644)
+	if err := PrepareCommitMsg(git, msgFile, "message", ""); err != nil {
+		t.Fatalf("PrepareCommitMsg failed: %v", err)
+	}
+	content, _ := os.ReadFile(msgFile)
+	if !strings.Contains(string(content), TrunkOverrideTrailer+": hotfix for incident 42") {
+		t.Errorf("expected override trailer, got %q", content)
+	}
+	os.Unsetenv(TrunkOverrideEnv)
+	exec.Command("git", "reset").Run()
+
+	// Case 3: repo opted out, and work off the trunk -> allowed
+	os.WriteFile(filepath.Join(dir, "services", "repoB", "b.txt"), []byte("b2"), 0644)
+	exec.Command("git", "add", "services/repoB").Run()
+	if err := PreCommit(git); err != nil {
+		t.Errorf("expected opted-out repo to pass, got: %v", err)
+	}
+	exec.Command("git", "reset").Run()
+	gitUtil.CreateBranch(dir, "feature")
+	exec.Command("git", "add", "services/repoA").Run()
+	if err := PreCommit(git); err != nil {
+		t.Errorf("expected feature branch commit to pass, got: %v", err)
+	}
+}
+
+func TestPreCommit_Scope(t *testing.T) {
+	git := gitUtil.NewExecClient()
+	tmpDir := t.TempDir()
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+	os.Chdir(tmpDir)
+
+	exec.Command("git", "init").Run()
+	exec.Command("git", "config", "user.email", "you@example.com").Run()
+	exec.Command("git", "config", "user.name", "Your Name").Run()
+
+	groveUtil.CreateGroveConfig(tmpDir, false)
+	groveUtil.RegisterRepoInConfig(tmpDir, []model.GGRepo{
+		{Name: "repoA", Path: "services/repoA"},
+		{Name: "repoB", Path: "services/repoB"},
+	})
+	exec.Command("git", "add", ".").Run()
+	exec.Command("git", "commit", "-m", "init gg.json").Run()
+	os.MkdirAll("services/repoA", 0755)
+	os.MkdirAll("services/repoB", 0755)
+
+	groveUtil.SetContextScope(git, tmpDir, "repoA")
+
+	// Case 1: scoped repo -> passes
+	os.WriteFile("services/repoA/file.txt", []byte("content"), 0644)
+	exec.Command("git", "add", "services/repoA").Run()
+	if err := PreCommit(git); err != nil {
+		t.Errorf("expected pass inside scope, got error: %v", err)
+	}
+	exec.Command("git", "reset").Run()
+
+	// Case 2: another repo -> rejected
+	os.WriteFile("services/repoB/file.txt", []byte("content"), 0644)
+	exec.Command("git", "add", "services/repoB").Run()
+	if err := PreCommit(git); err == nil || !strings.Contains(err.Error(), "scope violation") {
+		t.Errorf("expected scope violation for repoB, got: %v", err)
+	}
+	exec.Command("git", "reset").Run()
+
+	// Case 3: root-only commit -> rejected even though it is atomic
+	os.WriteFile("README.md", []byte("content"), 0644)
+	exec.Command("git", "add", "README.md").Run()
+	if err := PreCommit(git); err == nil || !strings.Contains(err.Error(), "gg scope --clear") {
+		t.Errorf("expected scope violation for root file, got: %v", err)
+	}
+
+	// Case 4: cleared scope -> root commit allowed again
+	groveUtil.ClearContextScope(git, tmpDir)
+	if err := PreCommit(git); err != nil {
+		t.Errorf("expected pass after clearing scope, got error: %v", err)
+	}
+}
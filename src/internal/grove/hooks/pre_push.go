@@ -0,0 +1,240 @@
+package hooks
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	gitUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/git"
+	groveUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/grove"
+)
+
+// PrePush validates every commit about to be pushed to remote. Git writes one line per pushed ref
+// to input: "<local ref> <local sha> <remote ref> <remote sha>".
+//
+// Unlike PreCommit it cannot be bypassed with `git commit --no-verify`, so it re-applies the
+// atomic commit rules to each new commit, refuses to push orphan history to a trunk ref, and
+// refuses direct trunk commits to registered paths that did not come through prepare-merge.
+func PrePush(git gitUtil.GitClient, remote string, input io.Reader) error {
+	root, err := git.RepoRoot()
+	if err != nil {
+		root, _ = os.Getwd()
+	}
+
+	var violations []string
+	scanner := bufio.NewScanner(input)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 4 {
+			continue
+		}
+		localRef, localSHA, remoteRef, remoteSHA := fields[0], fields[1], fields[2], fields[3]
+		if isZeroSHA(localSHA) {
+			// Branch deletion
+			continue
+		}
+
+		refViolations, err := checkPushedRef(git, root, remote, localRef, localSHA, remoteRef, remoteSHA)
+		if err != nil {
+			return err
+		}
+		violations = append(violations, refViolations...)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read pushed refs: %w", err)
+	}
+
+	if len(violations) > 0 {
+		return fmt.Errorf("push rejected:\n  - %s", strings.Join(violations, "\n  - "))
+	}
+	return nil
+}
+
+func checkPushedRef(git gitUtil.GitClient, root, remote, localRef, localSHA, remoteRef, remoteSHA string) ([]string, error) {
+	remoteBranch := strings.TrimPrefix(remoteRef, "refs/heads/")
+	onTrunk := remoteBranch != remoteRef && isTrunkBranch(git, root, remoteBranch)
+
+	// 1. Orphan history must never replace the trunk
+	if onTrunk {
+		if _, _, ok := groveUtil.ParseOrphanBranch(strings.TrimPrefix(localRef, "refs/heads/")); ok {
+			return []string{fmt.Sprintf("refusing to push orphan branch %s to trunk ref %s", localRef, remoteRef)}, nil
+		}
+		if exists, _ := git.FileExistsInBranch(root, localSHA, ".gg/gg.json"); !exists {
+			return []string{fmt.Sprintf("refusing to push %s to trunk ref %s: %.7s has no .gg/gg.json (orphan history?)", localRef, remoteRef, localSHA)}, nil
+		}
+	}
+
+	// 2. Every commit the remote does not have yet
+	commits, err := newCommits(git, root, remote, localSHA, remoteSHA)
+	if err != nil {
+		return nil, err
+	}
+
+	var violations []string
+	for _, sha := range commits {
+		reasons, err := checkCommit(git, root, sha, onTrunk)
+		if err != nil {
+			return nil, err
+		}
+		for _, reason := range reasons {
+			violations = append(violations, fmt.Sprintf("%s %.7s %s: %s", remoteBranch, sha, commitSubject(git, root, sha), reason))
+		}
+	}
+	return violations, nil
+}
+
+// checkCommit applies the atomic commit rules (using the gg.json of that commit) to a single commit.
+// On the trunk it also rejects edits to registered paths that were not produced by an integration.
+// Merge commits are checked through InspectCommit; commits without gg.json (orphan history) are not checked.
+func checkCommit(git gitUtil.GitClient, root string, sha string, onTrunk bool) ([]string, error) {
+	if exists, _ := git.FileExistsInBranch(root, sha, ".gg/gg.json"); !exists {
+		return nil, nil
+	}
+	config, err := groveUtil.LoadConfigFromGitRef(git, root, sha)
+	if err != nil {
+		return nil, err
+	}
+
+	change, err := InspectCommit(git, root, sha, config)
+	if err != nil {
+		return nil, err
+	}
+
+	var reasons []string
+	if err := CheckAtomicity(config, change.Own); err != nil {
+		reasons = append(reasons, err.Error())
+	}
+
+	if onTrunk && !hasTrunkOverride(git, root, sha) {
+		for _, repoName := range change.Attribution.RepoNames() {
+			if change.Integrated[repoName] {
+				continue
+			}
+			reasons = append(reasons, fmt.Sprintf("direct trunk commit edits registered repository '%s' outside a merge-prep integration; commit on its orphan branch (gg checkout %s) and integrate with gg prepare-merge", repoName, repoName))
+			break
+		}
+	}
+	return reasons, nil
+}
+
+// CommitChange describes what a single commit changes, attributed with a gg.json.
+type CommitChange struct {
+	Merge       bool
+	Files       []string               // files changed against the first parent
+	Attribution *groveUtil.Attribution // attribution of Files
+	// Own attributes the edits made by the commit itself. For a merge these are the files whose result
+	// matches none of its parents (conflict resolutions and evil-merge edits); the merged commits are
+	// checked on their own. Otherwise it is Attribution.
+	Own *groveUtil.Attribution
+	// Integrated holds the repositories integrated by prepare-merge in the commit or, for a merge,
+	// in the commits it brings in (GG-Repo trailers).
+	Integrated map[string]bool
+}
+
+// InspectCommit diffs sha against its first parent and attributes the result with config. It is shared
+// by the pre-push hook and gg verify, so a merge cannot carry edits past either.
+func InspectCommit(git gitUtil.GitClient, root string, sha string, config *groveUtil.GGConfig) (*CommitChange, error) {
+	parents, err := git.CommitParents(root, sha)
+	if err != nil {
+		return nil, err
+	}
+	change := &CommitChange{Merge: len(parents) > 1, Integrated: make(map[string]bool)}
+
+	integrations := sha + "^!"
+	if change.Merge {
+		integrations = parents[0] + ".." + sha
+		if change.Files, err = git.DiffNames(root, parents[0], sha); err != nil {
+			return nil, err
+		}
+	} else if change.Files, err = git.CommitFiles(root, sha); err != nil {
+		return nil, err
+	}
+	change.Attribution = groveUtil.AttributeFiles(config, change.Files)
+	change.Own = change.Attribution
+
+	if change.Merge {
+		own := change.Files
+		for _, parent := range parents[1:] {
+			changed, err := git.DiffNames(root, parent, sha)
+			if err != nil {
+				return nil, err
+			}
+			own = intersect(own, changed)
+		}
+		change.Own = groveUtil.AttributeFiles(config, own)
+	}
+
+	repos, err := git.TrailerValues(root, integrations, groveUtil.RepoTrailer)
+	if err != nil {
+		return nil, err
+	}
+	for _, repoName := range repos {
+		change.Integrated[repoName] = true
+	}
+	return change, nil
+}
+
+func intersect(a []string, b []string) []string {
+	in := make(map[string]bool, len(b))
+	for _, item := range b {
+		in[item] = true
+	}
+	result := []string{}
+	for _, item := range a {
+		if in[item] {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// newCommits lists the commits reachable from localSHA that the remote does not have, oldest first.
+func newCommits(git gitUtil.GitClient, root, remote, localSHA, remoteSHA string) ([]string, error) {
+	if !isZeroSHA(remoteSHA) {
+		if _, err := git.RevParse(root, remoteSHA+"^{commit}"); err == nil {
+			return git.RevList(root, "--reverse", remoteSHA+".."+localSHA)
+		}
+	}
+	// New branch, or the remote tip is unknown locally (force push without fetch)
+	return git.RevList(root, "--reverse", localSHA, "--not", "--remotes="+remote)
+}
+
+// isTrunkBranch reports whether branch is a GitGrove trunk: it owns orphan branches or is the sticky trunk.
+func isTrunkBranch(git gitUtil.GitClient, root string, branch string) bool {
+	if branch == "" || strings.HasPrefix(branch, "gg/") {
+		return false
+	}
+	if contextTrunk, _ := groveUtil.GetContextTrunk(git, root); contextTrunk == branch {
+		return true
+	}
+	orphans, err := git.ListBranches(root, "refs/heads/gg/"+branch+"/")
+	if err != nil {
+		return false
+	}
+	for _, orphan := range orphans {
+		if trunk, _, ok := groveUtil.ParseOrphanBranch(orphan); ok && trunk == branch {
+			return true
+		}
+	}
+	return false
+}
+
+// isIntegrationCommit reports whether the commit was created by prepare-merge (it carries a GG-Orphan-Commit trailer).
+func isIntegrationCommit(git gitUtil.GitClient, root string, sha string) bool {
+	values, err := git.TrailerValues(root, sha+"^!", groveUtil.OrphanCommitTrailer)
+	return err == nil && len(values) > 0
+}
+
+func commitSubject(git gitUtil.GitClient, root string, sha string) string {
+	info, err := git.GetCommitInfo(root, sha)
+	if err != nil {
+		return ""
+	}
+	return strings.SplitN(info.Message, "\n", 2)[0]
+}
+
+func isZeroSHA(sha string) bool {
+	return strings.Trim(sha, "0") == ""
+}
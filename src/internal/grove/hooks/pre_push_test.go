@@ -0,0 +1,149 @@
+package hooks
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	preparemerge "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/grove/prepare-merge"
+	"github.com/kuchuk-borom-debbarma/GitGrove/src/internal/testutil"
+	gitUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/git"
+)
+
+const zeroRef = "0000000000000000000000000000000000000000"
+
+// prePushChecker returns a function running PrePush the way git would for a push of localRef to remoteRef.
+func prePushChecker(git gitUtil.GitClient, dir string, remoteDir string) func(localRef, remoteRef string) error {
+	return func(localRef, remoteRef string) error {
+		sha, _ := gitUtil.RevParse(dir, localRef)
+		remoteSHA := zeroRef
+		if out, err := exec.Command("git", "-C", remoteDir, "rev-parse", "--verify", "--quiet", remoteRef).Output(); err == nil {
+			remoteSHA = strings.TrimSpace(string(out))
+		}
+		line := fmt.Sprintf("%s %s %s %s\n", localRef, sha, remoteRef, remoteSHA)
+		return PrePush(git, "origin", strings.NewReader(line))
+	}
+}
+
+func TestPrePush(t *testing.T) {
+	git := gitUtil.NewExecClient()
+	dir := testutil.TwoRepoWorkspace(t)
+	remoteDir := t.TempDir()
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+	testutil.Git(t, remoteDir, "init", "--bare")
+	testutil.Git(t, dir, "remote", "add", "origin", remoteDir)
+	os.Chdir(dir)
+
+	push := prePushChecker(git, dir, remoteDir)
+
+	// Case 1: initial trunk push -> passes
+	if err := push("refs/heads/main", "refs/heads/main"); err != nil {
+		t.Fatalf("expected clean trunk push to pass, got: %v", err)
+	}
+	exec.Command("git", "push", "-q", "origin", "main").Run()
+
+	// Case 2: orphan branch pushed under its own name -> passes; pushed to the trunk ref -> rejected
+	if err := push("refs/heads/gg/main/repoA", "refs/heads/gg/main/repoA"); err != nil {
+		t.Errorf("expected orphan branch push to pass, got: %v", err)
+	}
+	if err := push("refs/heads/gg/main/repoA", "refs/heads/main"); err == nil || !strings.Contains(err.Error(), "orphan branch") {
+		t.Errorf("expected orphan->trunk push to be rejected, got: %v", err)
+	}
+
+	// Case 3: mixed commit made with --no-verify on a feature branch -> rejected by the atomic rules
+	gitUtil.CreateBranch(dir, "feature")
+	os.WriteFile(filepath.Join(dir, "services", "repoA", "a.txt"), []byte("a2"), 0644)
+	os.WriteFile(filepath.Join(dir, "services", "repoB", "b.txt"), []byte("b2"), 0644)
+	gitUtil.CommitNoVerify(dir, []string{"."}, "Touch both")
+	if err := push("refs/heads/feature", "refs/heads/feature"); err == nil || !strings.Contains(err.Error(), "multiple registered repositories") {
+		t.Errorf("expected atomic violation, got: %v", err)
+	}
+
+	// Case 4: direct trunk commit to a registered path -> rejected
+	gitUtil.Checkout(dir, "main")
+	os.WriteFile(filepath.Join(dir, "services", "repoA", "a.txt"), []byte("direct"), 0644)
+	gitUtil.CommitNoVerify(dir, []string{"."}, "Direct edit")
+	err := push("refs/heads/main", "refs/heads/main")
+	if err == nil || !strings.Contains(err.Error(), "gg checkout repoA") {
+		t.Errorf("expected direct trunk edit to be rejected, got: %v", err)
+	}
+	exec.Command("git", "reset", "-q", "--hard", "HEAD~1").Run()
+
+	// Case 5: root-only trunk commit -> passes
+	os.WriteFile(filepath.Join(dir, "README.md"), []byte("readme"), 0644)
+	gitUtil.CommitNoVerify(dir, []string{"README.md"}, "Docs")
+	if err := push("refs/heads/main", "refs/heads/main"); err != nil {
+		t.Errorf("expected root-only trunk push to pass, got: %v", err)
+	}
+}
+
+func TestPrePush_Merges(t *testing.T) {
+	git := gitUtil.NewExecClient()
+	dir := testutil.TwoRepoWorkspace(t)
+	remoteDir := t.TempDir()
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+	testutil.Git(t, remoteDir, "init", "--bare")
+	testutil.Git(t, dir, "remote", "add", "origin", remoteDir)
+	testutil.Git(t, dir, "push", "-q", "origin", "main")
+	os.Chdir(dir)
+	push := prePushChecker(git, dir, remoteDir)
+
+	// Case 1: an integration concluded with a merge commit on a trunk that moved -> passes
+	testutil.Git(t, dir, "checkout", "-q", "gg/main/repoA")
+	testutil.WriteFile(t, dir, "a.txt", "a2")
+	testutil.Git(t, dir, "commit", "-q", "-am", "Orphan edit")
+	prep, err := preparemerge.PrepareMerge(git, dir, "")
+	if err != nil {
+		t.Fatalf("PrepareMerge failed: %v", err)
+	}
+	testutil.Git(t, dir, "checkout", "-q", "main")
+	testutil.WriteFile(t, dir, "README.md", "readme")
+	testutil.Git(t, dir, "add", "README.md")
+	testutil.Git(t, dir, "commit", "-q", "-m", "Docs")
+	testutil.Git(t, dir, "merge", "-q", "--no-ff", "-m", "Merge integration", prep.Branch)
+	if err := push("refs/heads/main", "refs/heads/main"); err != nil {
+		t.Errorf("expected the merged integration to pass, got: %v", err)
+	}
+	testutil.Git(t, dir, "push", "-q", "origin", "main")
+
+	// Case 2: evil merge on the trunk -> its own edit to repoA is rejected
+	testutil.Git(t, dir, "checkout", "-q", "-b", "docs")
+	testutil.WriteFile(t, dir, "README.md", "more docs")
+	testutil.Git(t, dir, "commit", "-q", "-am", "More docs")
+	testutil.Git(t, dir, "checkout", "-q", "main")
+	testutil.Git(t, dir, "merge", "-q", "--no-ff", "--no-commit", "docs")
+	testutil.WriteFile(t, dir, "services/repoA/a.txt", "evil")
+	testutil.Git(t, dir, "add", ".")
+	testutil.Git(t, dir, "commit", "-q", "-m", "Merge docs")
+	err = push("refs/heads/main", "refs/heads/main")
+	if err == nil || !strings.Contains(err.Error(), "Merge docs") || !strings.Contains(err.Error(), "registered repository 'repoA'") {
+		t.Errorf("expected the evil merge to be rejected, got: %v", err)
+	}
+
+	// Case 3: the same merge editing two repositories also breaks atomicity, on any branch
+	testutil.WriteFile(t, dir, "services/repoB/b.txt", "evil")
+	testutil.Git(t, dir, "commit", "-q", "--amend", "-am", "Merge docs")
+	if err := push("refs/heads/main", "refs/heads/feature"); err == nil || !strings.Contains(err.Error(), "multiple registered repositories") {
+		t.Errorf("expected an atomicity violation for the merge, got: %v", err)
+	}
+	testutil.Git(t, dir, "reset", "-q", "--hard", "origin/main")
+
+	// Case 4: a branch already on the remote (checked off the trunk) merged into the trunk -> rejected
+	testutil.Git(t, dir, "checkout", "-q", "-b", "sneaky")
+	testutil.WriteFile(t, dir, "services/repoA/a.txt", "sneaky")
+	testutil.Git(t, dir, "commit", "-q", "-am", "Sneaky edit")
+	if err := push("refs/heads/sneaky", "refs/heads/sneaky"); err != nil {
+		t.Fatalf("expected the feature branch push to pass, got: %v", err)
+	}
+	testutil.Git(t, dir, "push", "-q", "origin", "sneaky")
+	testutil.Git(t, dir, "checkout", "-q", "main")
+	testutil.Git(t, dir, "merge", "-q", "--no-ff", "-m", "Merge sneaky", "sneaky")
+	if err := push("refs/heads/main", "refs/heads/main"); err == nil || !strings.Contains(err.Error(), "Merge sneaky") {
+		t.Errorf("expected the merge bringing repoA edits to the trunk to be rejected, got: %v", err)
+	}
+}
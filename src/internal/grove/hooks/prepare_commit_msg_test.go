@@ -6,11 +6,13 @@ import (
 	"path/filepath"
 	"testing"
 
+	gitUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/git"
 	groveUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/grove"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestPrepareCommitMsg(t *testing.T) {
+	git := gitUtil.NewExecClient()
 	// Setup temporary directory acting as repo root
 	tmpDir, err := os.MkdirTemp("", "gitgrove-test-prepare-*")
 	if err != nil {
@@ -65,7 +67,7 @@ func TestPrepareCommitMsg(t *testing.T) {
 	msgFile := filepath.Join(tmpDir, "COMMIT_EDITMSG")
 	os.WriteFile(msgFile, []byte("initial commit"), 0644)
 
-	err = PrepareCommitMsg(msgFile, "", "")
+	err = PrepareCommitMsg(git, msgFile, "", "")
 	assert.NoError(t, err)
 
 	content, _ := os.ReadFile(msgFile)
@@ -80,7 +82,7 @@ func TestPrepareCommitMsg(t *testing.T) {
 	exec.Command("git", "add", ".").Run()
 
 	os.WriteFile(msgFile, []byte("root change"), 0644)
-	err = PrepareCommitMsg(msgFile, "", "")
+	err = PrepareCommitMsg(git, msgFile, "", "")
 	assert.NoError(t, err)
 	content, _ = os.ReadFile(msgFile)
 	assert.Equal(t, "root change", string(content))
@@ -98,7 +100,7 @@ func TestPrepareCommitMsg(t *testing.T) {
 	exec.Command("git", "add", ".").Run()
 
 	os.WriteFile(msgFile, []byte("another commit"), 0644)
-	err = PrepareCommitMsg(msgFile, "", "")
+	err = PrepareCommitMsg(git, msgFile, "", "")
 	assert.NoError(t, err)
 	content, _ = os.ReadFile(msgFile)
 	assert.Equal(t, "another commit", string(content))
@@ -134,15 +136,16 @@ func TestPrepareCommitMsg(t *testing.T) {
 	msgFile4 := filepath.Join(tmpDir, "COMMIT_EDITMSG_4")
 	os.WriteFile(msgFile4, []byte("orphan commit"), 0644)
 
-	err = PrepareCommitMsg(msgFile4, "", "")
+	err = PrepareCommitMsg(git, msgFile4, "", "")
 	assert.NoError(t, err)
 
 	content, _ = os.ReadFile(msgFile4)
 	assert.Equal(t, "[repoA] orphan commit", string(content))
 
 	// Case 5: Sticky Context Logic
-	// Switch to a new branch off trunk (no orphan prefix)
-	exec.Command("git", "checkout", "trunk").Run()
+	// Switch to a new branch off trunk (no orphan prefix). -f restores the .gg directory deleted
+	// on the orphan branch, as a real checkout of the trunk would have it.
+	exec.Command("git", "checkout", "-f", "trunk").Run()
 	exec.Command("git", "checkout", "-b", "feature/sticky-test").Run()
 
 	// Set sticky context
@@ -151,9 +154,21 @@ func TestPrepareCommitMsg(t *testing.T) {
 	msgFile5 := filepath.Join(tmpDir, "COMMIT_EDITMSG_5")
 	os.WriteFile(msgFile5, []byte("sticky commit"), 0644)
 
-	err = PrepareCommitMsg(msgFile5, "", "")
+	err = PrepareCommitMsg(git, msgFile5, "", "")
 	assert.NoError(t, err)
 
 	content, _ = os.ReadFile(msgFile5)
 	assert.Equal(t, "[repoA] sticky commit", string(content))
+
+	// Case 6: merge-prep branch (trunk tree, name ends in a timestamp) -> the staged files decide
+	exec.Command("git", "checkout", "-q", "-b", "gg/merge-prep/repoA/20261018-213140", "trunk").Run()
+	os.MkdirAll(filepath.Join(tmpDir, repoA), 0755)
+	os.WriteFile(testFile, []byte("fix check"), 0644)
+	exec.Command("git", "add", testFile).Run()
+
+	msgFile6 := filepath.Join(tmpDir, "COMMIT_EDITMSG_6")
+	os.WriteFile(msgFile6, []byte("fix check"), 0644)
+	assert.NoError(t, PrepareCommitMsg(git, msgFile6, "", ""))
+	content, _ = os.ReadFile(msgFile6)
+	assert.Equal(t, "[repoA] fix check", string(content))
 }
@@ -0,0 +1,64 @@
+package hooks
+
+import (
+	"fmt"
+
+	gitUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/git"
+	groveUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/grove"
+)
+
+// checkScope enforces the active scope lock (gg scope <repo>) on the trunk: every staged file must
+// belong to the scoped repository. Neutral files may accompany it, but root-only commits are rejected.
+func checkScope(git gitUtil.GitClient, root string, config *groveUtil.GGConfig, attribution *groveUtil.Attribution) error {
+	scope, _ := groveUtil.GetContextScope(git, root)
+	if scope == "" {
+		return nil
+	}
+	repo, exists := config.Repositories[scope]
+	if !exists {
+		return fmt.Errorf("active scope '%s' is not a registered repository; run 'gg scope --clear'", scope)
+	}
+
+	var outside []string
+	for _, repoName := range attribution.RepoNames() {
+		if repoName != scope {
+			outside = append(outside, attribution.Repos[repoName]...)
+		}
+	}
+	for _, sharedName := range attribution.SharedNames() {
+		outside = append(outside, attribution.Shared[sharedName]...)
+	}
+	outside = append(outside, attribution.Root...)
+	if len(attribution.Repos[scope]) == 0 {
+		// Neutral files only count as part of the scope when they accompany it
+		outside = append(outside, attribution.Neutral...)
+	}
+
+	if len(outside) > 0 {
+		return fmt.Errorf("scope violation: active scope is '%s' (%s) but the commit touches files outside it: %v\n"+
+			"Unstage them, or leave strict mode with 'gg scope --clear'.", scope, repo.Path, outside)
+	}
+	return nil
+}
+
+// checkScopeOffTrunk enforces the scope lock where gg.json is not checked out (orphan and feature
+// branches): all files there belong to the checked-out repository, which must be the scoped one.
+func checkScopeOffTrunk(git gitUtil.GitClient, root string) error {
+	scope, _ := groveUtil.GetContextScope(git, root)
+	if scope == "" {
+		return nil
+	}
+	_, repoName := groveUtil.ResolveRepoContext(git, root)
+	if repoName == "" || repoName == scope {
+		return nil
+	}
+	stagedFiles, err := git.GetStagedFiles(root)
+	if err != nil {
+		return fmt.Errorf("failed to get staged files: %w", err)
+	}
+	if len(stagedFiles) == 0 {
+		return nil
+	}
+	return fmt.Errorf("scope violation: active scope is '%s' but this branch belongs to repository '%s'.\n"+
+		"Switch with 'gg checkout %s', or leave strict mode with 'gg scope --clear'.", scope, repoName, scope)
+}
@@ -0,0 +1,77 @@
+package hooks
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	gitUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/git"
+	groveUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/grove"
+)
+
+const (
+	// TrunkOverrideTrailer records why a commit was allowed to edit a protected repository on the trunk.
+	TrunkOverrideTrailer = "GG-Trunk-Override"
+	// TrunkOverrideEnv lets a single commit bypass trunk protection. The pre-commit hook runs before the
+	// message exists, so the reason is taken from the environment and written as a TrunkOverrideTrailer
+	// by the prepare-commit-msg hook.
+	TrunkOverrideEnv = "GG_TRUNK_OVERRIDE"
+)
+
+// checkTrunkProtection rejects commits on the trunk that touch a repository with protect_trunk enabled.
+// Concluding a merge of a prepare-merge integration and explicit overrides are allowed.
+func checkTrunkProtection(git gitUtil.GitClient, root string, config *groveUtil.GGConfig, attribution *groveUtil.Attribution) error {
+	currentBranch, err := git.CurrentBranch(root)
+	if err != nil || !isTrunkBranch(git, root, currentBranch) {
+		return nil
+	}
+	if os.Getenv(TrunkOverrideEnv) != "" || isMergingIntegration(git, root) {
+		return nil
+	}
+
+	for _, repoName := range attribution.RepoNames() {
+		if !groveUtil.IsTrunkProtected(config, repoName) {
+			continue
+		}
+		return fmt.Errorf("trunk protection: repository '%s' (%s) cannot be edited directly on trunk '%s'.\n"+
+			"Make the change on its orphan branch and integrate it with gg prepare-merge:\n"+
+			"    gg checkout %s\n"+
+			"To override, commit with %s=\"<reason>\" (recorded as a %s trailer).",
+			repoName, config.Repositories[repoName].Path, currentBranch, repoName, TrunkOverrideEnv, TrunkOverrideTrailer)
+	}
+	return nil
+}
+
+// isMergingIntegration reports whether a merge of a prepare-merge result is being concluded.
+func isMergingIntegration(git gitUtil.GitClient, root string) bool {
+	gitDir, err := git.GitDir(root)
+	if err != nil {
+		return false
+	}
+	data, err := os.ReadFile(filepath.Join(gitDir, "MERGE_HEAD"))
+	if err != nil {
+		return false
+	}
+	for _, sha := range strings.Fields(string(data)) {
+		if isIntegrationCommit(git, root, sha) {
+			return true
+		}
+	}
+	return false
+}
+
+// recordTrunkOverride adds the TrunkOverrideTrailer to the message when TrunkOverrideEnv is set.
+func recordTrunkOverride(git gitUtil.GitClient, root string, msgFile string) error {
+	reason := strings.TrimSpace(os.Getenv(TrunkOverrideEnv))
+	if reason == "" {
+		return nil
+	}
+	return git.AddTrailer(root, msgFile, TrunkOverrideTrailer, reason)
+}
+
+// hasTrunkOverride reports whether the commit carries a TrunkOverrideTrailer.
+func hasTrunkOverride(git gitUtil.GitClient, root string, sha string) bool {
+	values, err := git.TrailerValues(root, sha+"^!", TrunkOverrideTrailer)
+	return err == nil && len(values) > 0
+}
@@ -1,12 +1,14 @@
 package initialize
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
 
+	installhooks "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/grove/install-hooks"
 	gitUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/git"
 	groveUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/grove"
 )
@@ -15,10 +17,17 @@ import (
 func Description() string {
 	return "Initialize: Establishes the current branch as the Trunk.\n" +
 		"- Creates .gg/gg.json registry\n" +
-		"- Installs pre-commit and prepare-commit-msg hooks\n" +
+		"- Installs pre-commit, prepare-commit-msg, commit-msg and pre-push hooks\n" +
 		"- Commits the configuration to the current branch"
 }
 
+// Result describes an initialized workspace.
+type Result struct {
+	Path         string `json:"path"`
+	Trunk        string `json:"trunk"`
+	AtomicCommit bool   `json:"atomic_commit"`
+}
+
 // Initialize establishes the "Trunk" for the GitGrove monorepo.
 //
 // Concept: The Trunk
@@ -33,12 +42,12 @@ func Description() string {
 //  3. Commit this configuration to the current branch, formally establishing it
 //     as the root of the GitGrove system.
 //     as the root of the GitGrove system.
-func Initialize(path string, atomicCommit bool) error {
+func Initialize(git gitUtil.GitClient, path string, atomicCommit bool) (*Result, error) {
 	path = filepath.Clean(path)
 	//Validations
 	//Validate if its a valid git repository
-	if err := gitUtil.IsGitRepository(path); err != nil {
-		return err
+	if err := git.IsGitRepository(path); err != nil {
+		return nil, err
 	}
 
 	// Validate git-grove is in PATH (required for hooks) ONLY if atomic commit is requested
@@ -46,14 +55,18 @@ func Initialize(path string, atomicCommit bool) error {
 		if _, err := exec.LookPath("git-grove"); err != nil {
 			if _, err2 := exec.LookPath("gg"); err2 != nil {
 				absPath, _ := filepath.Abs(os.Args[0])
-				return fmt.Errorf(getPathErrorMsg(absPath))
+				return nil, errors.New(getPathErrorMsg(absPath))
 			}
 		}
 	}
 
 	//Validate that there is no existing .gg/gg.json
-	if err := groveUtil.IsGroveInitialized(path); err != nil {
-		return err
+	initStatus, err := groveUtil.IsGroveInitialized(git, path)
+	if err != nil {
+		return nil, err
+	}
+	if err := initStatus.AlreadyInitializedError(path); err != nil {
+		return nil, err
 	}
 
 	//create .gg/gg.json file
@@ -61,21 +74,25 @@ func Initialize(path string, atomicCommit bool) error {
 	// AtomicCommit flag in Initialize controls the PATH validation (enforcement strength),
 	// but currently the pre-commit hook enforces logic whenever gg.json exists.
 	if err := groveUtil.CreateGroveConfig(path, true); err != nil {
-		return err
+		return nil, err
 	}
 
-	// Install hooks
-	if err := installHooks(path); err != nil {
-		return err
+	// Install hooks (existing hooks are chained, core.hooksPath is honored)
+	if err := installhooks.Install(git, path); err != nil {
+		return nil, err
 	}
 
 	//Commit this configuration to the current branch
 	// Use CommitNoVerify to prevent hook failure during initialization if the global binary is mismatched
-	if err := gitUtil.CommitNoVerify(path, []string{".gg/gg.json"}, "Initialize GitGrove"); err != nil {
-		return err
+	if err := git.CommitNoVerify(path, []string{".gg/gg.json"}, "Initialize GitGrove"); err != nil {
+		return nil, err
 	}
 
-	return nil
+	trunk, err := git.CurrentBranch(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Result{Path: path, Trunk: trunk, AtomicCommit: atomicCommit}, nil
 }
 
 func getPathErrorMsg(absPath string) string {
@@ -96,60 +113,3 @@ func getPathErrorMsg(absPath string) string {
 		"  OR\n"+
 		"  sudo ln -s %s /usr/local/bin/gg", filepath.Dir(absPath), absPath, absPath)
 }
-
-func installHooks(path string) error {
-	// Pre-commit
-	preCommitHookPath := filepath.Join(path, ".git", "hooks", "pre-commit")
-	preCommitContent := `#!/bin/sh
-# GitGrove Pre-commit Hook
-# This hook ensures atomic commits across the GitGrove monorepo.
-
-# Check if git-grove or gg is in PATH
-if command -v git-grove >/dev/null 2>&1; then
-    GG_CMD=git-grove
-elif command -v gg >/dev/null 2>&1; then
-    GG_CMD=gg
-else
-    echo "Warning: git-grove not found in PATH. Skipping atomic commit enforcement."
-    exit 0
-fi
-
-# Execute git-grove hook
-# We capture output to check for errors, but also allow stdout to pass through if needed
-OUTPUT=$($GG_CMD hook pre-commit 2>&1)
-EXIT_CODE=$?
-
-if [ $EXIT_CODE -ne 0 ]; then
-    echo "GitGrove Pre-commit Hook Failed:"
-    echo "$OUTPUT"
-    echo ""
-    echo "Tip: Ensure you have the latest version of git-grove installed and in your PATH."
-    exit $EXIT_CODE
-fi
-`
-	if err := os.WriteFile(preCommitHookPath, []byte(preCommitContent), 0755); err != nil {
-		return fmt.Errorf("failed to create pre-commit hook: %w", err)
-	}
-
-	// Prepare-commit-msg
-	prepareMsgHookPath := filepath.Join(path, ".git", "hooks", "prepare-commit-msg")
-	prepareMsgContent := `#!/bin/sh
-# GitGrove Prepare-commit-msg Hook
-
-if command -v git-grove >/dev/null 2>&1; then
-    GG_CMD=git-grove
-elif command -v gg >/dev/null 2>&1; then
-    GG_CMD=gg
-else
-    echo "Warning: git-grove not found in PATH. Context aware commit message will not work." >&2
-    exit 0
-fi
-
-$GG_CMD hook prepare-commit-msg "$1" "$2" "$3"
-`
-	if err := os.WriteFile(prepareMsgHookPath, []byte(prepareMsgContent), 0755); err != nil {
-		return fmt.Errorf("failed to create prepare-commit-msg hook: %w", err)
-	}
-
-	return nil
-}
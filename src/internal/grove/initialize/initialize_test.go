@@ -1,15 +1,19 @@
 package initialize_test
 
 import (
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"testing"
 
 	"github.com/kuchuk-borom-debbarma/GitGrove/src/internal/grove/initialize"
+	gitUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/git"
+	groveUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/grove"
 )
 
 func TestInitialize(t *testing.T) {
+	git := gitUtil.NewExecClient()
 	// Create a temporary directory
 	tempDir, err := os.MkdirTemp("", "gitgrove-test")
 	if err != nil {
@@ -33,9 +37,13 @@ func TestInitialize(t *testing.T) {
 	cmd.Run()
 
 	// Run Initialize
-	if err := initialize.Initialize(tempDir, false); err != nil {
+	result, err := initialize.Initialize(git, tempDir, false)
+	if err != nil {
 		t.Fatalf("Initialize failed: %v", err)
 	}
+	if branch, _ := gitUtil.CurrentBranch(tempDir); result.Trunk != branch || result.Path != tempDir || result.AtomicCommit {
+		t.Errorf("unexpected result: %+v", result)
+	}
 
 	// Verify .gg/gg.json exists
 	configPath := filepath.Join(tempDir, ".gg", "gg.json")
@@ -53,4 +61,24 @@ func TestInitialize(t *testing.T) {
 	if string(output) != "Initialize GitGrove\n" && string(output) != "Initialize GitGrove\n\n" {
 		t.Errorf("Unexpected commit message: %q", string(output))
 	}
+
+	// Initialized: reported as a status, and a second Initialize is refused
+	status, err := groveUtil.IsGroveInitialized(git, tempDir)
+	if err != nil || status.State != groveUtil.InitializedTrunk {
+		t.Errorf("expected InitializedTrunk, got %+v (%v)", status, err)
+	}
+	if _, err := initialize.Initialize(git, tempDir, false); !errors.Is(err, groveUtil.ErrAlreadyInitialized) {
+		t.Errorf("expected ErrAlreadyInitialized, got %v", err)
+	}
+}
+
+func TestInitialize_NotGitRepository(t *testing.T) {
+	git := gitUtil.NewExecClient()
+	dir := t.TempDir()
+	if status, err := groveUtil.IsGroveInitialized(git, dir); err != nil || status.Initialized() {
+		t.Errorf("expected not initialized, got %+v (%v)", status, err)
+	}
+	if _, err := initialize.Initialize(git, dir, false); !errors.Is(err, gitUtil.ErrNotGitRepository) {
+		t.Errorf("expected ErrNotGitRepository, got %v", err)
+	}
 }
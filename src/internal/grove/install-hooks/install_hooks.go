@@ -0,0 +1,323 @@
+package installhooks
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	gitUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/git"
+)
+
+// ManagedMarker identifies hook scripts written by GitGrove.
+const ManagedMarker = "# GitGrove managed hook"
+
+// HookVersion is written into every generated script. Bump it whenever hookScript or a hook
+// body changes so that gg hooks status can report scripts written by an older binary.
+const HookVersion = 2
+
+// versionPrefix precedes HookVersion in generated scripts.
+const versionPrefix = "# gitgrove-hook-version: "
+
+// ChainedSuffix is appended to a pre-existing hook when GitGrove moves it aside.
+const ChainedSuffix = ".gg-chained"
+
+// Positions of a chained hook relative to GitGrove's logic, read by the scripts at run time from
+// ChainPositionKey. ChainBefore is the default.
+const (
+	ChainBefore = "before"
+	ChainAfter  = "after"
+)
+
+// ChainPositionKey returns the git config key (gitgrove.chain.<hook>) choosing when a hook's chained
+// script runs, e.g. `git config gitgrove.chain.pre-push after`.
+func ChainPositionKey(hookName string) string {
+	return "gitgrove.chain." + hookName
+}
+
+// hookSpec describes one git hook installed by GitGrove.
+type hookSpec struct {
+	Name    string // git hook name
+	Title   string
+	Purpose string
+	Missing string // warning printed when git-grove is not in PATH
+	Body    string // runs with $GG_CMD set to the git-grove binary
+	Stdin   bool   // git passes data on stdin that both the chained hook and GitGrove need
+}
+
+var hookSpecs = []hookSpec{
+	{
+		Name:    "pre-commit",
+		Title:   "Pre-commit",
+		Purpose: "This hook ensures atomic commits across the GitGrove monorepo.",
+		Missing: "Warning: git-grove not found in PATH. Skipping atomic commit enforcement.",
+		Body: `# Execute git-grove hook
+# We capture output to check for errors, but also allow stdout to pass through if needed
+OUTPUT=$($GG_CMD hook pre-commit 2>&1)
+EXIT_CODE=$?
+
+if [ $EXIT_CODE -ne 0 ]; then
+    echo "GitGrove Pre-commit Hook Failed:"
+    echo "$OUTPUT"
+    echo ""
+    echo "Tip: Ensure you have the latest version of git-grove installed and in your PATH."
+    exit $EXIT_CODE
+fi
+`,
+	},
+	{
+		Name:    "prepare-commit-msg",
+		Title:   "Prepare-commit-msg",
+		Purpose: "This hook prefixes commit messages with the repository name.",
+		Missing: "Warning: git-grove not found in PATH. Context aware commit message will not work.",
+		Body:    `$GG_CMD hook prepare-commit-msg "$1" "$2" "$3"` + "\n",
+	},
+	{
+		Name:    "commit-msg",
+		Title:   "Commit-msg",
+		Purpose: "This hook validates the final message against the repository's commit rules.",
+		Missing: "Warning: git-grove not found in PATH. Skipping commit message validation.",
+		Body:    `$GG_CMD hook commit-msg "$1"` + "\n",
+	},
+	{
+		Name:    "pre-push",
+		Title:   "Pre-push",
+		Purpose: "This hook re-validates every pushed commit, including those committed with --no-verify.",
+		Missing: "Warning: git-grove not found in PATH. Skipping push validation.",
+		Body:    `printf '%s\n' "$GG_STDIN" | $GG_CMD hook pre-push "$1" "$2"` + "\n",
+		Stdin:   true,
+	},
+}
+
+// Description returns a description of the hook installation.
+func Description() string {
+	return "Install Hooks: Installs the GitGrove git hooks.\n" +
+		"- Writes pre-commit, prepare-commit-msg, commit-msg and pre-push into the hooks directory (honors core.hooksPath)\n" +
+		"- Existing hooks are moved aside and still run, before GitGrove's checks or after them (git config gitgrove.chain.<hook> after)\n" +
+		"- Safe to run repeatedly; gg hooks uninstall restores the original hooks\n" +
+		"- gg hooks status reports missing or outdated scripts; gg hooks upgrade rewrites outdated ones"
+}
+
+// ManagedHooks returns the names of the hooks GitGrove installs.
+func ManagedHooks() []string {
+	names := make([]string, 0, len(hookSpecs))
+	for _, spec := range hookSpecs {
+		names = append(names, spec.Name)
+	}
+	return names
+}
+
+// HooksDir returns the directory git reads hooks from: core.hooksPath if set, otherwise the hooks
+// directory of the common git dir (shared by all worktrees).
+func HooksDir(git gitUtil.GitClient, repoPath string) (string, error) {
+	return git.GitPath(repoPath, "hooks")
+}
+
+// Install writes the GitGrove hooks. A hook that was not written by GitGrove is renamed to
+// <hook>.gg-chained and invoked by the GitGrove hook before or after its own logic (see
+// ChainPositionKey). Re-running Install
+// only refreshes the GitGrove scripts. If another tool replaced a GitGrove hook that already
+// chains a different script, Install refuses rather than overwrite the chained one.
+func Install(git gitUtil.GitClient, repoPath string) error {
+	hooksDir, err := HooksDir(git, repoPath)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		return fmt.Errorf("failed to create hooks directory %s: %w", hooksDir, err)
+	}
+
+	// Check every hook first so a conflict leaves the hooks directory untouched
+	chain := make(map[string]bool)
+	for _, spec := range hookSpecs {
+		hookPath := filepath.Join(hooksDir, spec.Name)
+		content, err := os.ReadFile(hookPath)
+		if err != nil || IsManaged(string(content)) {
+			continue
+		}
+		chained, err := os.ReadFile(hookPath + ChainedSuffix)
+		if err == nil && string(chained) != string(content) {
+			return fmt.Errorf("cannot install %s hook: %s and %s are different scripts not written by GitGrove; combine them into %s and remove %s",
+				spec.Name, hookPath, hookPath+ChainedSuffix, hookPath+ChainedSuffix, hookPath)
+		}
+		chain[spec.Name] = true
+	}
+
+	for _, spec := range hookSpecs {
+		hookPath := filepath.Join(hooksDir, spec.Name)
+		if chain[spec.Name] {
+			// Somebody else's hook: keep it in the chain (an identical chained copy is simply replaced)
+			if err := os.Rename(hookPath, hookPath+ChainedSuffix); err != nil {
+				return fmt.Errorf("failed to move existing %s hook aside: %w", spec.Name, err)
+			}
+		}
+
+		if err := os.WriteFile(hookPath, []byte(hookScript(spec)), 0755); err != nil {
+			return fmt.Errorf("failed to create %s hook: %w", spec.Name, err)
+		}
+	}
+	return nil
+}
+
+// Uninstall removes the GitGrove hooks and restores any hooks they were chained to.
+func Uninstall(git gitUtil.GitClient, repoPath string) error {
+	hooksDir, err := HooksDir(git, repoPath)
+	if err != nil {
+		return err
+	}
+
+	for _, spec := range hookSpecs {
+		hookPath := filepath.Join(hooksDir, spec.Name)
+		if content, err := os.ReadFile(hookPath); err == nil && IsManaged(string(content)) {
+			if err := os.Remove(hookPath); err != nil {
+				return fmt.Errorf("failed to remove %s hook: %w", spec.Name, err)
+			}
+		}
+
+		chainedPath := hookPath + ChainedSuffix
+		if _, err := os.Stat(chainedPath); err == nil {
+			if _, err := os.Stat(hookPath); err == nil {
+				return fmt.Errorf("cannot restore %s: %s exists and was not written by GitGrove", chainedPath, hookPath)
+			}
+			if err := os.Rename(chainedPath, hookPath); err != nil {
+				return fmt.Errorf("failed to restore original %s hook: %w", spec.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// HookStatus describes one installed GitGrove hook.
+type HookStatus struct {
+	Name      string `json:"name"`
+	Path      string `json:"path"`
+	Installed bool   `json:"installed"` // a script exists at Path
+	Managed   bool   `json:"managed"`   // the script was written by GitGrove
+	Version   int    `json:"version"`   // version marker of a managed script, 0 for scripts older than the marker
+	Chained   bool   `json:"chained"`   // a pre-existing hook runs before or after GitGrove's checks
+	Position  string `json:"position"`  // ChainBefore or ChainAfter, where a chained hook runs
+}
+
+// Outdated reports whether the hook is a GitGrove script written by an older binary.
+func (s HookStatus) Outdated() bool {
+	return s.Managed && s.Version < HookVersion
+}
+
+// Status inspects every hook GitGrove installs.
+func Status(git gitUtil.GitClient, repoPath string) ([]HookStatus, error) {
+	hooksDir, err := HooksDir(git, repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]HookStatus, 0, len(hookSpecs))
+	for _, spec := range hookSpecs {
+		status := HookStatus{Name: spec.Name, Path: filepath.Join(hooksDir, spec.Name)}
+		if content, err := os.ReadFile(status.Path); err == nil {
+			status.Installed = true
+			status.Managed = IsManaged(string(content))
+			status.Version = ScriptVersion(string(content))
+		}
+		if _, err := os.Stat(status.Path + ChainedSuffix); err == nil {
+			status.Chained = true
+		}
+		status.Position = ChainBefore
+		if position, _ := git.GetLocalConfig(repoPath, ChainPositionKey(spec.Name)); position == ChainAfter {
+			status.Position = ChainAfter
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// Upgrade rewrites the GitGrove hooks written by an older binary and returns their names. Missing
+// hooks and hooks that were not written by GitGrove are left alone (see Install).
+func Upgrade(git gitUtil.GitClient, repoPath string) ([]string, error) {
+	statuses, err := Status(git, repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var upgraded []string
+	for i, status := range statuses {
+		if !status.Outdated() {
+			continue
+		}
+		if err := os.WriteFile(status.Path, []byte(hookScript(hookSpecs[i])), 0755); err != nil {
+			return upgraded, fmt.Errorf("failed to upgrade %s hook: %w", status.Name, err)
+		}
+		upgraded = append(upgraded, status.Name)
+	}
+	return upgraded, nil
+}
+
+// ResolveBinary returns the command the hooks will run (git-grove or gg), or an empty string
+// when neither is in PATH and the hooks skip their checks.
+func ResolveBinary() string {
+	for _, name := range []string{"git-grove", "gg"} {
+		if path, err := exec.LookPath(name); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// ScriptVersion returns the version marker of a hook script, or 0 if it has none.
+func ScriptVersion(content string) int {
+	for _, line := range strings.Split(content, "\n") {
+		if value, ok := strings.CutPrefix(line, versionPrefix); ok {
+			version, err := strconv.Atoi(strings.TrimSpace(value))
+			if err == nil {
+				return version
+			}
+		}
+	}
+	return 0
+}
+
+// IsManaged reports whether a hook script was written by GitGrove, including scripts from
+// versions before the marker was introduced (they all delegate to "$GG_CMD hook").
+func IsManaged(content string) bool {
+	return strings.Contains(content, ManagedMarker) || strings.Contains(content, "$GG_CMD hook ")
+}
+
+func hookScript(spec hookSpec) string {
+	var b strings.Builder
+	b.WriteString("#!/bin/sh\n")
+	fmt.Fprintf(&b, "# GitGrove %s Hook\n", spec.Title)
+	fmt.Fprintf(&b, "# %s\n", spec.Purpose)
+	fmt.Fprintf(&b, "%s%d\n", versionPrefix, HookVersion)
+	fmt.Fprintf(&b, "%s: edits are overwritten by 'gg hooks install'; 'gg hooks uninstall' restores the previous hook.\n\n", ManagedMarker)
+
+	if spec.Stdin {
+		b.WriteString("# Git passes the pushed refs on stdin; both hooks in the chain need them\n")
+		b.WriteString("GG_STDIN=$(cat)\n\n")
+	}
+
+	fmt.Fprintf(&b, "# The hook that was installed before GitGrove runs first, or last with %s=%s\n", ChainPositionKey(spec.Name), ChainAfter)
+	fmt.Fprintf(&b, "CHAINED=\"$(dirname \"$0\")/%s%s\"\n", spec.Name, ChainedSuffix)
+	fmt.Fprintf(&b, "CHAIN_POSITION=$(git config --local --get %s)\n", ChainPositionKey(spec.Name))
+	b.WriteString("run_chained() {\n    if [ -x \"$CHAINED\" ]; then\n")
+	if spec.Stdin {
+		b.WriteString("        printf '%s\\n' \"$GG_STDIN\" | \"$CHAINED\" \"$@\" || exit $?\n")
+	} else {
+		b.WriteString("        \"$CHAINED\" \"$@\" || exit $?\n")
+	}
+	fmt.Fprintf(&b, "    fi\n}\nif [ \"$CHAIN_POSITION\" != \"%s\" ]; then\n    run_chained \"$@\"\nfi\n\n", ChainAfter)
+
+	b.WriteString(`# Check if git-grove or gg is in PATH
+if command -v git-grove >/dev/null 2>&1; then
+    GG_CMD=git-grove
+elif command -v gg >/dev/null 2>&1; then
+    GG_CMD=gg
+else
+    GG_CMD=""
+`)
+	fmt.Fprintf(&b, "    echo \"%s\" >&2\nfi\n\n", spec.Missing)
+	b.WriteString("if [ -n \"$GG_CMD\" ]; then\n")
+	b.WriteString(spec.Body)
+	fmt.Fprintf(&b, "fi\n\nif [ \"$CHAIN_POSITION\" = \"%s\" ]; then\n    run_chained \"$@\"\nfi\n", ChainAfter)
+	return b.String()
+}
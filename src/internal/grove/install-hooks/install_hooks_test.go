@@ -0,0 +1,240 @@
+package installhooks_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	installhooks "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/grove/install-hooks"
+	"github.com/kuchuk-borom-debbarma/GitGrove/src/internal/testutil"
+	gitUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/git"
+)
+
+func TestInstall_ChainsExistingHooks(t *testing.T) {
+	git := gitUtil.NewExecClient()
+	dir := testutil.GitRepo(t)
+	hooksDir := filepath.Join(dir, ".git", "hooks")
+	marker := filepath.Join(dir, ".git", "team-hook-ran")
+
+	// A team hook that must keep working
+	teamHook := "#!/bin/sh\ntouch \"" + marker + "\"\n"
+	os.WriteFile(filepath.Join(hooksDir, "pre-commit"), []byte(teamHook), 0755)
+
+	if err := installhooks.Install(git, dir); err != nil {
+		t.Fatalf("Install failed: %v", err)
+	}
+	// Idempotent: a second run must not chain GitGrove to itself
+	if err := installhooks.Install(git, dir); err != nil {
+		t.Fatalf("second Install failed: %v", err)
+	}
+
+	chained, err := os.ReadFile(filepath.Join(hooksDir, "pre-commit"+installhooks.ChainedSuffix))
+	if err != nil || string(chained) != teamHook {
+		t.Fatalf("expected team hook to be chained unchanged, got %q (%v)", chained, err)
+	}
+	for _, name := range installhooks.ManagedHooks() {
+		content, err := os.ReadFile(filepath.Join(hooksDir, name))
+		if err != nil || !installhooks.IsManaged(string(content)) {
+			t.Errorf("expected managed %s hook, got %q (%v)", name, content, err)
+		}
+	}
+
+	// The chained hook still runs on commit
+	os.WriteFile(filepath.Join(dir, "file.txt"), []byte("content"), 0644)
+	exec.Command("git", "-C", dir, "add", ".").Run()
+	if out, err := exec.Command("git", "-C", dir, "commit", "-m", "test").CombinedOutput(); err != nil {
+		t.Fatalf("commit failed: %v: %s", err, out)
+	}
+	if _, err := os.Stat(marker); err != nil {
+		t.Error("expected the chained team hook to run")
+	}
+
+	// Uninstall restores the original and removes the rest
+	if err := installhooks.Uninstall(git, dir); err != nil {
+		t.Fatalf("Uninstall failed: %v", err)
+	}
+	restored, _ := os.ReadFile(filepath.Join(hooksDir, "pre-commit"))
+	if string(restored) != teamHook {
+		t.Errorf("expected original pre-commit to be restored, got %q", restored)
+	}
+	if _, err := os.Stat(filepath.Join(hooksDir, "commit-msg")); !os.IsNotExist(err) {
+		t.Error("expected commit-msg hook to be removed")
+	}
+}
+
+func TestInstall_KeepsExistingChainedHook(t *testing.T) {
+	git := gitUtil.NewExecClient()
+	dir := testutil.GitRepo(t)
+	hooksDir := filepath.Join(dir, ".git", "hooks")
+	hookPath := filepath.Join(hooksDir, "pre-commit")
+
+	teamHook := "#!/bin/sh\necho team\n"
+	os.WriteFile(hookPath, []byte(teamHook), 0755)
+	if err := installhooks.Install(git, dir); err != nil {
+		t.Fatalf("Install failed: %v", err)
+	}
+
+	// Another tool (husky, pre-commit, ...) rewrites the hook after installation
+	toolHook := "#!/bin/sh\necho tool\n"
+	os.WriteFile(hookPath, []byte(toolHook), 0755)
+	os.Remove(filepath.Join(hooksDir, "commit-msg"))
+
+	err := installhooks.Install(git, dir)
+	if err == nil || !strings.Contains(err.Error(), "pre-commit"+installhooks.ChainedSuffix) {
+		t.Fatalf("expected Install to refuse overwriting the chained hook, got: %v", err)
+	}
+	chained, _ := os.ReadFile(hookPath + installhooks.ChainedSuffix)
+	current, _ := os.ReadFile(hookPath)
+	if string(chained) != teamHook || string(current) != toolHook {
+		t.Errorf("expected both scripts untouched, got chained %q and current %q", chained, current)
+	}
+	if _, err := os.Stat(filepath.Join(hooksDir, "commit-msg")); !os.IsNotExist(err) {
+		t.Error("expected a refused Install to write no hooks")
+	}
+
+	// The same script being reinstalled is safe to chain again
+	os.WriteFile(hookPath, []byte(teamHook), 0755)
+	if err := installhooks.Install(git, dir); err != nil {
+		t.Fatalf("Install over an identical chained hook failed: %v", err)
+	}
+	chained, _ = os.ReadFile(hookPath + installhooks.ChainedSuffix)
+	current, _ = os.ReadFile(hookPath)
+	if string(chained) != teamHook || !installhooks.IsManaged(string(current)) {
+		t.Errorf("expected the team hook chained behind GitGrove, got chained %q and current %q", chained, current)
+	}
+}
+
+func TestInstall_HooksPathAndWorktrees(t *testing.T) {
+	git := gitUtil.NewExecClient()
+	dir := testutil.GitRepo(t)
+
+	// core.hooksPath wins over .git/hooks
+	exec.Command("git", "-C", dir, "config", "core.hooksPath", ".githooks").Run()
+	if err := installhooks.Install(git, dir); err != nil {
+		t.Fatalf("Install failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".githooks", "pre-commit")); err != nil {
+		t.Errorf("expected hooks in core.hooksPath: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".git", "hooks", "commit-msg")); !os.IsNotExist(err) {
+		t.Error("expected no hooks in .git/hooks when core.hooksPath is set")
+	}
+	exec.Command("git", "-C", dir, "config", "--unset", "core.hooksPath").Run()
+
+	// Linked worktrees share the hooks of the main repository
+	os.WriteFile(filepath.Join(dir, "file.txt"), []byte("content"), 0644)
+	exec.Command("git", "-C", dir, "add", ".").Run()
+	exec.Command("git", "-C", dir, "commit", "--no-verify", "-m", "initial").Run()
+	worktree := filepath.Join(t.TempDir(), "wt")
+	if out, err := exec.Command("git", "-C", dir, "worktree", "add", "-b", "wt", worktree).CombinedOutput(); err != nil {
+		t.Fatalf("worktree add failed: %v: %s", err, out)
+	}
+
+	hooksDir, err := installhooks.HooksDir(git, worktree)
+	if err != nil {
+		t.Fatalf("HooksDir failed: %v", err)
+	}
+	expected, _ := filepath.EvalSymlinks(filepath.Join(dir, ".git", "hooks"))
+	actual, _ := filepath.EvalSymlinks(hooksDir)
+	if !strings.EqualFold(actual, expected) {
+		t.Errorf("expected worktree hooks dir %s, got %s", expected, actual)
+	}
+	if err := installhooks.Install(git, worktree); err != nil {
+		t.Fatalf("Install from worktree failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".git", "hooks", "pre-push")); err != nil {
+		t.Errorf("expected hooks in the common git dir: %v", err)
+	}
+}
+
+func TestStatusAndUpgrade(t *testing.T) {
+	git := gitUtil.NewExecClient()
+	dir := testutil.GitRepo(t)
+	hooksDir := filepath.Join(dir, ".git", "hooks")
+
+	// Fresh clone: nothing installed
+	statuses, err := installhooks.Status(git, dir)
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	for _, status := range statuses {
+		if status.Installed || status.Managed {
+			t.Errorf("expected %s to be missing, got %+v", status.Name, status)
+		}
+	}
+
+	if err := installhooks.Install(git, dir); err != nil {
+		t.Fatalf("Install failed: %v", err)
+	}
+	// Simulate a script written before the version marker existed
+	legacy := "#!/bin/sh\n$GG_CMD hook commit-msg \"$1\"\n"
+	os.WriteFile(filepath.Join(hooksDir, "commit-msg"), []byte(legacy), 0755)
+
+	statuses, _ = installhooks.Status(git, dir)
+	for _, status := range statuses {
+		if !status.Managed {
+			t.Errorf("expected %s to be managed", status.Name)
+		}
+		if expected := status.Name == "commit-msg"; status.Outdated() != expected {
+			t.Errorf("expected %s outdated=%v, got version %d", status.Name, expected, status.Version)
+		}
+	}
+
+	upgraded, err := installhooks.Upgrade(git, dir)
+	if err != nil {
+		t.Fatalf("Upgrade failed: %v", err)
+	}
+	if len(upgraded) != 1 || upgraded[0] != "commit-msg" {
+		t.Errorf("expected only commit-msg to be upgraded, got %v", upgraded)
+	}
+	content, _ := os.ReadFile(filepath.Join(hooksDir, "commit-msg"))
+	if installhooks.ScriptVersion(string(content)) != installhooks.HookVersion {
+		t.Errorf("expected upgraded script to carry version %d, got:\n%s", installhooks.HookVersion, content)
+	}
+}
+
+func TestInstall_ChainPosition(t *testing.T) {
+	git := gitUtil.NewExecClient()
+	dir := testutil.GitRepo(t)
+	hookPath := filepath.Join(dir, ".git", "hooks", "commit-msg")
+	order := filepath.Join(dir, ".git", "order")
+
+	// A fake git-grove recording when GitGrove's logic runs
+	bin := t.TempDir()
+	os.WriteFile(filepath.Join(bin, "git-grove"), []byte("#!/bin/sh\necho gitgrove >> \""+order+"\"\n"), 0755)
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	os.WriteFile(hookPath, []byte("#!/bin/sh\necho team >> \""+order+"\"\n"), 0755)
+	if err := installhooks.Install(git, dir); err != nil {
+		t.Fatalf("Install failed: %v", err)
+	}
+
+	run := func() string {
+		t.Helper()
+		os.Remove(order)
+		cmd := exec.Command(hookPath, "COMMIT_EDITMSG")
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("hook failed: %v: %s", err, out)
+		}
+		content, _ := os.ReadFile(order)
+		return strings.Join(strings.Fields(string(content)), " ")
+	}
+
+	if got := run(); got != "team gitgrove" {
+		t.Errorf("expected the chained hook to run first by default, got %q", got)
+	}
+	testutil.Git(t, dir, "config", installhooks.ChainPositionKey("commit-msg"), installhooks.ChainAfter)
+	if got := run(); got != "gitgrove team" {
+		t.Errorf("expected the chained hook to run last, got %q", got)
+	}
+
+	statuses, _ := installhooks.Status(git, dir)
+	for _, status := range statuses {
+		if expected := status.Name == "commit-msg"; (status.Position == installhooks.ChainAfter) != expected {
+			t.Errorf("unexpected position for %s: %s", status.Name, status.Position)
+		}
+	}
+}
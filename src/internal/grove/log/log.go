@@ -0,0 +1,206 @@
+package log
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	gitUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/git"
+	groveUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/grove"
+)
+
+// Where a commit lives.
+const (
+	LocationTrunk  = "trunk"  // only on the trunk (e.g. committed directly to the trunk, not in the orphan branch yet)
+	LocationOrphan = "orphan" // only on the orphan branch (not integrated yet)
+	LocationBoth   = "both"   // on the orphan branch and integrated into the trunk
+)
+
+// Entry is one commit of the unified history. A commit that lives in both places is listed once,
+// with both SHAs (they differ when the trunk copy was replayed or subtree split).
+type Entry struct {
+	TrunkSHA    string    `json:"trunk_sha,omitempty"`
+	OrphanSHA   string    `json:"orphan_sha,omitempty"`
+	Location    string    `json:"location"`
+	AuthorName  string    `json:"author_name"`
+	AuthorEmail string    `json:"author_email"`
+	Date        time.Time `json:"date"` // author date
+	Subject     string    `json:"subject"`
+	Body        string    `json:"body,omitempty"`
+}
+
+// SHA returns the trunk SHA if the commit is on the trunk, else the orphan SHA.
+func (e Entry) SHA() string {
+	if e.TrunkSHA != "" {
+		return e.TrunkSHA
+	}
+	return e.OrphanSHA
+}
+
+// Options filter the history; they are passed to git log as --since and --author.
+type Options struct {
+	Since  string
+	Author string
+}
+
+// Description returns a description of the unified log.
+func Description() string {
+	return "Log: Shows the history of a repository across the trunk and its orphan branch.\n" +
+		"- Trunk commits touching the repository's path and orphan branch commits, newest first\n" +
+		"- Integrated commits are listed once, marked trunk, orphan or both\n" +
+		"- Merge commits are left out"
+}
+
+// Log returns the unified history of repoName, newest first. Empty trunk or repoName are inferred from context.
+func Log(git gitUtil.GitClient, ggRepoPath string, trunk string, repoName string, opts Options) ([]Entry, error) {
+	contextTrunk, contextRepo := groveUtil.ResolveRepoContext(git, ggRepoPath)
+	if trunk == "" {
+		trunk = contextTrunk
+	}
+	if trunk == "" {
+		trunk, _ = git.CurrentBranch(ggRepoPath)
+	}
+	if repoName == "" {
+		repoName = contextRepo
+	}
+	if repoName == "" {
+		return nil, fmt.Errorf("repository name is required when there is no repository context")
+	}
+
+	config, err := groveUtil.LoadConfigFromGitRef(git, ggRepoPath, trunk)
+	if err != nil {
+		return nil, err
+	}
+	repo, exists := config.Repositories[repoName]
+	if !exists {
+		return nil, &groveUtil.RepoNotRegisteredError{Name: repoName}
+	}
+	orphanBranch := fmt.Sprintf("gg/%s/%s", trunk, repoName)
+
+	trunkCommits, err := readCommits(git, ggRepoPath, opts, trunk, "--", repo.Path)
+	if err != nil {
+		return nil, err
+	}
+	var orphanCommits []commit
+	notIntegrated := map[string]bool{}
+	if _, err := git.RevParse(ggRepoPath, "refs/heads/"+orphanBranch); err == nil {
+		if orphanCommits, err = readCommits(git, ggRepoPath, opts, orphanBranch); err != nil {
+			return nil, err
+		}
+		// Orphan commits reachable from the trunk were brought in by a subtree merge
+		shas, err := git.RevList(ggRepoPath, orphanBranch, "--not", trunk)
+		if err != nil {
+			return nil, err
+		}
+		for _, sha := range shas {
+			notIntegrated[sha] = true
+		}
+	}
+
+	// Trunk copies of orphan commits: replayed commits name their original in a trailer,
+	// subtree split commits keep the author, date and subject of the trunk commit.
+	byOrphanSHA := map[string]*commit{}
+	byKey := map[string]*commit{}
+	for i := range trunkCommits {
+		c := &trunkCommits[i]
+		for _, sha := range c.orphanCommits {
+			byOrphanSHA[sha] = c
+		}
+		byKey[c.key()] = c
+	}
+
+	entries := []Entry{}
+	matched := map[string]bool{}
+	for _, c := range orphanCommits {
+		entry := c.entry()
+		entry.OrphanSHA = c.sha
+		entry.Location = LocationOrphan
+		trunkCopy := byOrphanSHA[c.sha]
+		if trunkCopy == nil {
+			trunkCopy = byKey[c.key()]
+		}
+		if trunkCopy != nil && !matched[trunkCopy.sha] {
+			matched[trunkCopy.sha] = true
+			entry.TrunkSHA = trunkCopy.sha
+			entry.Location = LocationBoth
+		} else if !notIntegrated[c.sha] {
+			// The commit itself is part of the trunk history
+			entry.TrunkSHA = c.sha
+			entry.Location = LocationBoth
+		}
+		entries = append(entries, entry)
+	}
+	for _, c := range trunkCommits {
+		if matched[c.sha] {
+			continue
+		}
+		entry := c.entry()
+		entry.TrunkSHA = c.sha
+		entry.Location = LocationTrunk
+		entries = append(entries, entry)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Date.After(entries[j].Date)
+	})
+	return entries, nil
+}
+
+// commit is a parsed git log record.
+type commit struct {
+	sha, authorName, authorEmail string
+	date                         time.Time
+	subject, body                string
+	orphanCommits                []string // GG-Orphan-Commit trailer values
+}
+
+// key identifies the same change across rewritten copies of a commit.
+func (c *commit) key() string {
+	return strings.Join([]string{c.authorEmail, c.date.UTC().Format(time.RFC3339), c.subject}, "\x00")
+}
+
+func (c *commit) entry() Entry {
+	return Entry{AuthorName: c.authorName, AuthorEmail: c.authorEmail, Date: c.date, Subject: c.subject, Body: c.body}
+}
+
+// readCommits runs git log (without merges) over args and parses the commits.
+func readCommits(git gitUtil.GitClient, ggRepoPath string, opts Options, args ...string) ([]commit, error) {
+	format := fmt.Sprintf("--format=%%H%%x00%%an%%x00%%ae%%x00%%aI%%x00%%(trailers:key=%s,valueonly,separator=%%x2C)%%x00%%s%%x00%%b%%x1e",
+		groveUtil.OrphanCommitTrailer)
+	logArgs := []string{format, "--no-merges"}
+	if opts.Since != "" {
+		logArgs = append(logArgs, "--since="+opts.Since)
+	}
+	if opts.Author != "" {
+		logArgs = append(logArgs, "--author="+opts.Author)
+	}
+	output, err := git.Log(ggRepoPath, append(logArgs, args...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	commits := []commit{}
+	for _, record := range strings.Split(output, "\x1e") {
+		fields := strings.Split(strings.TrimLeft(record, "\n"), "\x00")
+		if len(fields) != 7 {
+			continue
+		}
+		date, _ := time.Parse(time.RFC3339, fields[3])
+		c := commit{
+			sha:         fields[0],
+			authorName:  fields[1],
+			authorEmail: fields[2],
+			date:        date,
+			subject:     fields[5],
+			body:        strings.TrimSpace(fields[6]),
+		}
+		for _, sha := range strings.Split(fields[4], ",") {
+			if sha = strings.TrimSpace(sha); sha != "" {
+				c.orphanCommits = append(c.orphanCommits, sha)
+			}
+		}
+		commits = append(commits, c)
+	}
+	return commits, nil
+}
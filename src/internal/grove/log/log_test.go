@@ -0,0 +1,87 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	preparemerge "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/grove/prepare-merge"
+	"github.com/kuchuk-borom-debbarma/GitGrove/src/internal/testutil"
+	gitUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/git"
+)
+
+func TestLog(t *testing.T) {
+	git := gitUtil.NewExecClient()
+	dir := testutil.TwoRepoWorkspace(t)
+
+	integrate := func(strategy string) {
+		t.Helper()
+		prep, err := preparemerge.PrepareMergeWithStrategy(git, dir, "", strategy)
+		if err != nil {
+			t.Fatalf("PrepareMerge failed: %v", err)
+		}
+		gitUtil.Checkout(dir, "main")
+		if err := gitUtil.Merge(dir, prep.Branch); err != nil {
+			t.Fatalf("Merge failed: %v", err)
+		}
+		gitUtil.DeleteBranch(dir, prep.Branch, true)
+	}
+
+	// Integrated with a subtree merge
+	gitUtil.Checkout(dir, "gg/main/repoA")
+	os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a2"), 0644)
+	gitUtil.CommitNoVerify(dir, []string{"a.txt"}, "Merged change")
+	integrate(preparemerge.StrategyMerge)
+
+	// Trunk only, plus a commit to another repository that must not show up
+	os.WriteFile(filepath.Join(dir, "services", "repoA", "a.txt"), []byte("a3"), 0644)
+	gitUtil.CommitNoVerify(dir, []string{"."}, "Trunk change")
+	os.WriteFile(filepath.Join(dir, "services", "repoB", "b.txt"), []byte("b2"), 0644)
+	gitUtil.CommitNoVerify(dir, []string{"."}, "Other repository")
+
+	// Integrated by replay, then one more orphan commit
+	gitUtil.Checkout(dir, "gg/main/repoA")
+	os.WriteFile(filepath.Join(dir, "r.txt"), []byte("r"), 0644)
+	gitUtil.CommitNoVerify(dir, []string{"r.txt"}, "Replayed change")
+	replayed, _ := gitUtil.RevParse(dir, "HEAD")
+	integrate(preparemerge.StrategyReplay)
+	gitUtil.Checkout(dir, "gg/main/repoA")
+	os.WriteFile(filepath.Join(dir, "o.txt"), []byte("o"), 0644)
+	gitUtil.CommitNoVerify(dir, []string{"o.txt"}, "Orphan change")
+
+	entries, err := Log(git, dir, "main", "repoA", Options{})
+	if err != nil {
+		t.Fatalf("Log failed: %v", err)
+	}
+	locations := map[string]string{}
+	for _, entry := range entries {
+		if _, seen := locations[entry.Subject]; seen {
+			t.Errorf("commit %q listed twice", entry.Subject)
+		}
+		locations[entry.Subject] = entry.Location
+		if entry.Subject == "Replayed change" && (entry.OrphanSHA != replayed || entry.TrunkSHA == "" || entry.TrunkSHA == replayed) {
+			t.Errorf("expected the replayed commit to carry both SHAs: %+v", entry)
+		}
+	}
+	expected := map[string]string{
+		"Add services":    LocationBoth,
+		"Merged change":   LocationBoth,
+		"Trunk change":    LocationTrunk,
+		"Replayed change": LocationBoth,
+		"Orphan change":   LocationOrphan,
+	}
+	for subject, location := range expected {
+		if locations[subject] != location {
+			t.Errorf("expected %q to be %s, got %q", subject, location, locations[subject])
+		}
+	}
+	if len(entries) != len(expected) {
+		t.Errorf("expected %d entries, got %+v", len(expected), entries)
+	}
+
+	// Filters are passed to git log
+	entries, err = Log(git, dir, "main", "repoA", Options{Author: "nobody"})
+	if err != nil || len(entries) != 0 {
+		t.Errorf("expected no commits by nobody, got %+v (err: %v)", entries, err)
+	}
+}
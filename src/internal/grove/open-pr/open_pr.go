@@ -0,0 +1,108 @@
+package openpr
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kuchuk-borom-debbarma/GitGrove/src/internal/forge"
+	gitUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/git"
+	groveUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/grove"
+)
+
+// Description returns a description of the open pull request process.
+func Description() string {
+	return "Open Pull Request: Publishes a prepare-merge branch for review.\n" +
+		"- Pushes the current gg/merge-prep branch to the configured remote\n" +
+		"- Opens a Pull Request against the trunk on GitHub or GitLab\n" +
+		"- Labels the Pull Request with the repository's tags"
+}
+
+// OpenPullRequest pushes the current merge-prep branch and opens a pull request against the trunk.
+//
+// The forge is configured in the "forge" section of gg.json (provider, project, base_url, remote, token_env).
+// The API token is read from the environment variable named by token_env
+// (defaults to GITHUB_TOKEN or GITLAB_TOKEN).
+func OpenPullRequest(git gitUtil.GitClient, ggRepoPath string) (*forge.PullRequestResult, error) {
+	ggRepoPath = filepath.Clean(ggRepoPath)
+
+	// 1. Context Detection: must be on gg/merge-prep/<repoName>/<timestamp>
+	currentBranch, err := git.CurrentBranch(ggRepoPath)
+	if err != nil {
+		return nil, err
+	}
+	repoName, err := repoNameFromMergePrepBranch(currentBranch)
+	if err != nil {
+		return nil, err
+	}
+
+	trunkBranch, err := groveUtil.GetContextTrunk(git, ggRepoPath)
+	if err != nil || trunkBranch == "" {
+		return nil, fmt.Errorf("unknown trunk branch. Run 'gg prepare-merge' to create the merge-prep branch first")
+	}
+
+	// 2. Configuration (merge-prep branches are cut from trunk, so gg.json is on disk)
+	config, err := groveUtil.LoadConfig(ggRepoPath)
+	if err != nil {
+		return nil, err
+	}
+	if config.Forge == nil {
+		return nil, fmt.Errorf("no forge configured. Add a \"forge\" section to .gg/gg.json")
+	}
+	repoConfig, exists := config.Repositories[repoName]
+	if !exists {
+		return nil, &groveUtil.RepoNotRegisteredError{Name: repoName}
+	}
+
+	f, err := forge.New(*config.Forge, os.Getenv(forge.TokenEnv(*config.Forge)))
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Push
+	remote := forge.RemoteName(*config.Forge)
+	if err := git.Push(ggRepoPath, remote, currentBranch); err != nil {
+		return nil, err
+	}
+
+	// 4. Open the Pull Request
+	commits, err := git.LogSubjects(ggRepoPath, trunkBranch, currentBranch)
+	if err != nil {
+		return nil, err
+	}
+
+	pr := forge.PullRequest{
+		Title:  fmt.Sprintf("[%s] Integrate %s into %s", repoName, repoName, trunkBranch),
+		Body:   buildBody(repoName, repoConfig.Path, trunkBranch, commits),
+		Head:   currentBranch,
+		Base:   trunkBranch,
+		Labels: repoConfig.Tags,
+	}
+
+	return f.OpenPullRequest(pr)
+}
+
+// repoNameFromMergePrepBranch extracts <repoName> from gg/merge-prep/<repoName>/<timestamp>.
+func repoNameFromMergePrepBranch(branch string) (string, error) {
+	parts := strings.Split(branch, "/")
+	if len(parts) < 4 || parts[0] != "gg" || parts[1] != "merge-prep" {
+		return "", fmt.Errorf("current branch '%s' is not a prepare-merge branch (gg/merge-prep/<repo>/<timestamp>)", branch)
+	}
+	return strings.Join(parts[2:len(parts)-1], "/"), nil
+}
+
+func buildBody(repoName, repoPath, trunkBranch string, commits []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Integrates the isolated history of `%s` (`%s`) into `%s`.\n\n", repoName, repoPath, trunkBranch)
+	if len(commits) == 0 {
+		b.WriteString("No new commits.\n")
+	} else {
+		b.WriteString("### Commits\n")
+		for _, c := range commits {
+			fmt.Fprintf(&b, "- %s\n", c)
+		}
+	}
+	b.WriteString("\n_Generated by GitGrove._\n")
+	return b.String()
+}
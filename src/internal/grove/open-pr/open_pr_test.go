@@ -0,0 +1,88 @@
+package openpr
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kuchuk-borom-debbarma/GitGrove/src/internal/forge"
+	preparemerge "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/grove/prepare-merge"
+	"github.com/kuchuk-borom-debbarma/GitGrove/src/internal/testutil"
+	gitUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/git"
+	groveUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/grove"
+	"github.com/kuchuk-borom-debbarma/GitGrove/src/model"
+)
+
+func TestOpenPullRequest(t *testing.T) {
+	git := gitUtil.NewExecClient()
+	dir := testutil.Workspace(t,
+		map[string]string{"backend/serviceA/main.go": "package main"},
+		model.GGRepo{Name: "service-a", Path: "backend/serviceA", Tags: []string{"backend"}},
+	)
+	remoteDir := t.TempDir()
+	testutil.Git(t, remoteDir, "init", "--bare")
+	testutil.Git(t, dir, "remote", "add", "origin", remoteDir)
+
+	// Work in the orphan branch and prepare the merge
+	gitUtil.Checkout(dir, "gg/main/service-a")
+	os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() {}"), 0644)
+	if err := gitUtil.Commit(dir, []string{"main.go"}, "Add main func"); err != nil {
+		t.Fatalf("Commit in orphan failed: %v", err)
+	}
+	if _, err := preparemerge.PrepareMerge(git, dir, ""); err != nil {
+		t.Fatalf("PrepareMerge failed: %v", err)
+	}
+	branch, _ := gitUtil.CurrentBranch(dir)
+
+	var got map[string]string
+	var gotLabels map[string][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/labels") {
+			json.NewDecoder(r.Body).Decode(&gotLabels)
+			w.Write([]byte(`[]`))
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"number": 1, "html_url": "https://example.test/pull/1"}`))
+	}))
+	defer server.Close()
+
+	// Point the forge at the stub
+	config, _ := groveUtil.LoadConfig(dir)
+	config.Forge = &forge.Config{Provider: "github", BaseURL: server.URL, Project: "acme/mono", TokenEnv: "GG_TEST_TOKEN"}
+	data, _ := json.MarshalIndent(config, "", "  ")
+	os.WriteFile(filepath.Join(dir, ".gg", "gg.json"), data, 0644)
+	t.Setenv("GG_TEST_TOKEN", "secret")
+
+	result, err := OpenPullRequest(git, dir)
+	if err != nil {
+		t.Fatalf("OpenPullRequest failed: %v", err)
+	}
+	if result.URL != "https://example.test/pull/1" {
+		t.Errorf("unexpected URL: %s", result.URL)
+	}
+
+	if got["head"] != branch || got["base"] != "main" {
+		t.Errorf("unexpected head/base: %v", got)
+	}
+	if got["title"] != "[service-a] Integrate service-a into main" {
+		t.Errorf("unexpected title: %q", got["title"])
+	}
+	if !strings.Contains(got["body"], "Add main func") {
+		t.Errorf("expected body to list orphan commits, got %q", got["body"])
+	}
+	if len(gotLabels["labels"]) != 1 || gotLabels["labels"][0] != "backend" {
+		t.Errorf("unexpected labels: %v", gotLabels)
+	}
+
+	// Branch must have been pushed
+	if err := exec.Command("git", "-C", remoteDir, "rev-parse", "--verify", branch).Run(); err != nil {
+		t.Errorf("expected %s to be pushed to the remote", branch)
+	}
+}
@@ -5,11 +5,13 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"time"
 
 	gitUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/git"
 	groveUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/grove"
+	"github.com/kuchuk-borom-debbarma/GitGrove/src/model"
 )
 
 // Description returns a description of the prepare merge process.
@@ -17,22 +19,51 @@ func Description() string {
 	return "Prepare Merge: Prepares work for integration into the Trunk.\n" +
 		"- Switches to trunk\n" +
 		"- Creates a temporary merge-prep branch\n" +
-		"- Merges changes from the orphan branch (restoring directory structure)\n" +
-		"- Excludes .gg/trunk artifact"
+		"- Merges (or replays) changes from the orphan branch (restoring directory structure)\n" +
+		"- Excludes .gg/trunk artifact\n" +
+		"- Runs the repository's pre-integration checks"
 }
 
-// PrepareMerge handles the logic for preparing a merge from an orphan branch to the trunk.
-func PrepareMerge(ggRepoPath string, repoNameArg string) error {
+// Integration strategies.
+const (
+	// StrategyMerge joins the orphan history with a subtree merge commit (default).
+	StrategyMerge = "merge"
+	// StrategyReplay re-creates each orphan commit on the trunk, giving a linear history.
+	StrategyReplay = "replay"
+)
+
+// Result describes the merge-prep branch created by PrepareMerge.
+type Result struct {
+	Repo         string                 `json:"repo"`
+	Trunk        string                 `json:"trunk"`
+	OrphanBranch string                 `json:"orphan_branch"`
+	Branch       string                 `json:"branch"` // the gg/merge-prep/<repo>/<timestamp> branch created
+	Strategy     string                 `json:"strategy"`
+	Commits      []string               `json:"commits"` // orphan commits integrated, oldest first
+	Checks       *groveUtil.CheckReport `json:"checks,omitempty"`
+	Warnings     []string               `json:"warnings"`
+}
+
+// PrepareMerge handles the logic for preparing a merge from an orphan branch to the trunk,
+// using the integration strategy configured in gg.json.
+func PrepareMerge(git gitUtil.GitClient, ggRepoPath string, repoNameArg string) (*Result, error) {
+	return PrepareMergeWithStrategy(git, ggRepoPath, repoNameArg, "")
+}
+
+// PrepareMergeWithStrategy is PrepareMerge with an explicit integration strategy ("merge" or "replay").
+// An empty strategy falls back to gg.json's integration_strategy, then to "merge".
+//
+// When the pre-integration checks fail, the branch is kept and both the Result and an error are returned.
+func PrepareMergeWithStrategy(git gitUtil.GitClient, ggRepoPath string, repoNameArg string, strategy string) (*Result, error) {
 	ggRepoPath = filepath.Clean(ggRepoPath)
 	// 1. Context Detection
-	currentBranch, err := gitUtil.CurrentBranch(ggRepoPath)
+	currentBranch, err := git.CurrentBranch(ggRepoPath)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	var targetRepoName string
 	var trunkBranch string = "main" // Default fallback
-	initialBranch := currentBranch
 
 	if strings.HasPrefix(currentBranch, "gg/") {
 		// Orphan Branch Context: gg/<trunk>/<repoName>
@@ -45,34 +76,28 @@ func PrepareMerge(ggRepoPath string, repoNameArg string) error {
 			trunkBranch = "main"
 		}
 
-		fmt.Printf("Detected orphan branch context for repo: %s (trunk: %s)\n", targetRepoName, trunkBranch)
-
 		// We need to switch to trunk branch first
-		fmt.Printf("Switching to trunk '%s'...\n", trunkBranch)
-		if err := gitUtil.Checkout(ggRepoPath, trunkBranch); err != nil {
-			return fmt.Errorf("failed to checkout trunk '%s': %w", trunkBranch, err)
+		if err := git.Checkout(ggRepoPath, trunkBranch); err != nil {
+			return nil, fmt.Errorf("failed to checkout trunk '%s': %w", trunkBranch, err)
 		}
 	} else {
 		// Standard Context (Trunk or Deep Feature Branch)
 		// Check for Sticky Context first
-		stickyRepo, errRepo := groveUtil.GetContextRepo(ggRepoPath)
-		stickyTrunk, errTrunk := groveUtil.GetContextTrunk(ggRepoPath)
+		stickyRepo, errRepo := groveUtil.GetContextRepo(git, ggRepoPath)
+		stickyTrunk, errTrunk := groveUtil.GetContextTrunk(git, ggRepoPath)
 
 		if errRepo == nil && stickyRepo != "" && errTrunk == nil && stickyTrunk != "" {
 			// Found sticky context!
 			targetRepoName = stickyRepo
 			trunkBranch = stickyTrunk
-			fmt.Printf("Detected sticky context for repo: %s (trunk: %s)\n", targetRepoName, trunkBranch)
-
 			// Switch to trunk
-			fmt.Printf("Switching to trunk '%s'...\n", trunkBranch)
-			if err := gitUtil.Checkout(ggRepoPath, trunkBranch); err != nil {
-				return fmt.Errorf("failed to checkout trunk '%s': %w", trunkBranch, err)
+			if err := git.Checkout(ggRepoPath, trunkBranch); err != nil {
+				return nil, fmt.Errorf("failed to checkout trunk '%s': %w", trunkBranch, err)
 			}
 		} else {
 			// No sticky context, rely on explicit arg or assume we are ON trunk
 			if repoNameArg == "" {
-				return fmt.Errorf("repository name is required when not in an orphan branch (and no sticky context found)")
+				return nil, fmt.Errorf("repository name is required when not in an orphan branch (and no sticky context found)")
 			}
 			targetRepoName = repoNameArg
 			trunkBranch = currentBranch // We assume we are on trunk
@@ -87,43 +112,99 @@ func PrepareMerge(ggRepoPath string, repoNameArg string) error {
 		configPath = filepath.Join(ggRepoPath, ".gg", "gg.json")
 		// Double check if path is correct or if we should rely on LoadConfig error?
 		// os.Stat check is good for specific error message.
-		return fmt.Errorf("gitgrove is not initialized in %s", ggRepoPath)
+		return nil, fmt.Errorf("%w in %s", groveUtil.ErrNotInitialized, ggRepoPath)
 	}
 
 	config, err := groveUtil.LoadConfig(ggRepoPath)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	repoConfig, exists := config.Repositories[targetRepoName]
 	if !exists {
-		return fmt.Errorf("repository '%s' not registered in gitgrove", targetRepoName)
+		return nil, &groveUtil.RepoNotRegisteredError{Name: targetRepoName}
+	}
+
+	if strategy == "" {
+		strategy = config.IntegrationStrategy
+	}
+	if strategy == "" {
+		strategy = StrategyMerge
+	}
+	if strategy != StrategyMerge && strategy != StrategyReplay {
+		return nil, fmt.Errorf("unknown integration strategy '%s' (expected %s or %s)", strategy, StrategyMerge, StrategyReplay)
 	}
 
-	// 3. Branch Preparation
-	// 3. Branch Preparation
+	// 3. Pending commits (everything on the orphan branch after the last recorded integration)
 	orphanBranchName := fmt.Sprintf("gg/%s/%s", trunkBranch, targetRepoName)
+	base, err := groveUtil.IntegrationBase(git, ggRepoPath, trunkBranch, orphanBranchName, targetRepoName, repoConfig.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the integration base of '%s': %w", targetRepoName, err)
+	}
+	pending, err := groveUtil.PendingCommitsSince(git, ggRepoPath, trunkBranch, orphanBranchName, base)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute pending commits for '%s': %w", targetRepoName, err)
+	}
+	if len(pending) == 0 {
+		return nil, fmt.Errorf("nothing to integrate: %s has no commits pending for %s", orphanBranchName, trunkBranch)
+	}
+
+	// Replay re-creates single-parent commits; it would drop conflict resolutions made in orphan merges
+	if strategy == StrategyReplay {
+		for _, commit := range pending {
+			parents, err := git.CommitParents(ggRepoPath, commit)
+			if err != nil {
+				return nil, err
+			}
+			if len(parents) > 1 {
+				return nil, fmt.Errorf("cannot replay %s: merge commit %s is pending (use the %s strategy)", orphanBranchName, commit, StrategyMerge)
+			}
+		}
+	}
+
+	// 3.1. Branch Preparation
 	timestamp := time.Now().Format("20060102-150405")
 	prepareBranchName := fmt.Sprintf("gg/merge-prep/%s/%s", targetRepoName, timestamp)
+	result := &Result{
+		Repo:         targetRepoName,
+		Trunk:        trunkBranch,
+		OrphanBranch: orphanBranchName,
+		Branch:       prepareBranchName,
+		Strategy:     strategy,
+		Commits:      pending,
+		Warnings:     []string{},
+	}
 
-	fmt.Printf("Creating prepare-merge branch: %s\n", prepareBranchName)
 	// We are currently on Trunk (main)
-	if err := gitUtil.CreateBranch(ggRepoPath, prepareBranchName); err != nil {
-		return fmt.Errorf("failed to create branch %s: %w", prepareBranchName, err)
+	if err := git.CreateBranch(ggRepoPath, prepareBranchName); err != nil {
+		return nil, fmt.Errorf("failed to create branch %s: %w", prepareBranchName, err)
 	}
 
-	// 4. Merge
-	fmt.Printf("Merging changes from %s...\n", orphanBranchName)
-	if err := gitUtil.SubtreeMerge(ggRepoPath, repoConfig.Path, orphanBranchName); err != nil {
-		return fmt.Errorf("failed to merge orphan branch %s: %w", orphanBranchName, err)
+	// 4. Merge (or Replay)
+	if strategy == StrategyReplay {
+		if _, err := replayOrphanCommits(git, ggRepoPath, targetRepoName, repoConfig.Path, pending); err != nil {
+			return nil, fmt.Errorf("failed to replay orphan branch %s: %w", orphanBranchName, err)
+		}
+	} else {
+		orphanTip, err := git.RevParse(ggRepoPath, orphanBranchName)
+		if err != nil {
+			return nil, err
+		}
+		message, err := groveUtil.WithIntegrationTrailers(git, ggRepoPath, "Merge orphan branch "+orphanBranchName, targetRepoName, orphanTip)
+		if err != nil {
+			return nil, err
+		}
+		// Only changes since the integration base are merged; .gg/trunk is an orphan-only artifact
+		if err := git.SubtreeMerge(ggRepoPath, repoConfig.Path, orphanBranchName, base, message, ".gg/trunk"); err != nil {
+			return nil, fmt.Errorf("failed to merge orphan branch %s: %w", orphanBranchName, err)
+		}
 	}
 
 	// 4.1. Exclude .gg/trunk if present
 	trunkFilePath := filepath.Join(ggRepoPath, ".gg", "trunk")
 	if _, err := os.Stat(trunkFilePath); err == nil {
-		fmt.Println("Removing .gg/trunk from merge result...")
 		if err := os.Remove(trunkFilePath); err != nil {
-			return fmt.Errorf("failed to remove .gg/trunk: %w", err)
+			return nil, fmt.Errorf("failed to remove .gg/trunk: %w", err)
 		}
 		// We need to stage this removal so it's part of the next commit or if we are amending?
 		// Subtree merge usually commits.
@@ -135,38 +216,32 @@ func PrepareMerge(ggRepoPath string, repoNameArg string) error {
 		// Or just create a new commit "Exclude .gg/trunk".
 		// Or `git rm .gg/trunk` and commit.
 
-		cmd := exec.Command("git", "rm", ".gg/trunk")
-		cmd.Dir = ggRepoPath
-		if err := cmd.Run(); err != nil {
+		if err := git.RemovePaths(ggRepoPath, ".gg/trunk"); err != nil {
 			// If git rm fails (maybe not tracked?), try just removing it.
 			// But if it was merged, it is tracked.
 		}
 
-		if err := gitUtil.Commit(ggRepoPath, []string{".gg/trunk"}, "Exclude .gg/trunk from merge"); err != nil {
+		if err := git.Commit(ggRepoPath, []string{".gg/trunk"}, "Exclude .gg/trunk from merge"); err != nil {
 			// If commiting failed, maybe nothing to commit?
 		}
 	}
 
-	// 5. Success
-	fmt.Println("\nSuccess! Prepare-merge branch created.")
-	fmt.Printf("Branch: %s\n", prepareBranchName)
-	fmt.Println("Review the changes and submit a Pull Request to merge into main.")
-
-	// If we started on orphan, we are now on the new branch. This is the desired behavior ("prepare for merge").
-	if initialBranch != "main" && !strings.HasPrefix(initialBranch, "prepare-merge") {
-		// If user was on orphan, they are now on prepare-merge branch.
-		// If user was on main, they are now on prepare-merge branch.
-		// Logic holds consistent.
+	// 5. Pre-integration checks (run on the merge-prep branch, in the repo's directory)
+	if len(repoConfig.Checks) > 0 {
+		result.Checks = runChecks(ggRepoPath, repoConfig, prepareBranchName)
+		if err := groveUtil.SaveCheckReport(git, ggRepoPath, result.Checks); err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("failed to record check results: %v", err))
+		}
 	}
 
 	// 6. Set context for the new prepare-merge branch
 	// We want the user to stay in the "orphan" feel even in prepare-merge branch?
 	// Yes, usually.
-	if err := groveUtil.SetContextRepo(ggRepoPath, targetRepoName); err != nil {
-		fmt.Printf("Warning: Failed to set sticky context repo: %v\n", err)
+	if err := groveUtil.SetContextRepo(git, ggRepoPath, targetRepoName); err != nil {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("failed to set sticky context repo: %v", err))
 	}
-	if err := groveUtil.SetContextTrunk(ggRepoPath, trunkBranch); err != nil {
-		fmt.Printf("Warning: Failed to set sticky context trunk: %v\n", err)
+	if err := groveUtil.SetContextTrunk(git, ggRepoPath, trunkBranch); err != nil {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("failed to set sticky context trunk: %v", err))
 	}
 	// The orphan branch logic usually points to the specific orphan branch name,
 	// but here we are in a merge-prep branch.
@@ -176,9 +251,69 @@ func PrepareMerge(ggRepoPath string, repoNameArg string) error {
 	// If we set it to the original orphan branch, 'Return to Orphan' will go back to the source.
 	// Which is probably what we want if they want to abandon the merge prep.
 	// Let's set it to the original orphan branch name!
-	if err := groveUtil.SetContextOrphan(ggRepoPath, orphanBranchName); err != nil {
-		fmt.Printf("Warning: Failed to set sticky context orphan: %v\n", err)
+	if err := groveUtil.SetContextOrphan(git, ggRepoPath, orphanBranchName); err != nil {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("failed to set sticky context orphan: %v", err))
+	}
+
+	// Failing checks leave the branch in place (so it can be fixed) but are reported as an error.
+	if result.Checks != nil && !result.Checks.Passed {
+		return result, fmt.Errorf("pre-integration checks failed for '%s' on branch %s", targetRepoName, prepareBranchName)
 	}
 
-	return nil
+	return result, nil
+}
+
+// runChecks executes each configured check command in the repository's directory and collects the results.
+func runChecks(ggRepoPath string, repo model.GGRepo, branch string) *groveUtil.CheckReport {
+	report := &groveUtil.CheckReport{
+		Repo:   repo.Name,
+		Branch: branch,
+		RanAt:  time.Now(),
+		Passed: true,
+	}
+	repoDir := filepath.Join(ggRepoPath, repo.Path)
+
+	for _, check := range repo.Checks {
+		start := time.Now()
+		cmd := shellCommand(check)
+		cmd.Dir = repoDir
+		output, err := cmd.CombinedOutput()
+
+		result := groveUtil.CheckResult{
+			Command:  check,
+			Passed:   err == nil,
+			Duration: time.Since(start),
+			Output:   tail(string(output), maxCheckOutput),
+		}
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			result.ExitCode = exitErr.ExitCode()
+		} else if err != nil {
+			result.ExitCode = -1
+			result.Output = err.Error()
+		}
+
+		if !result.Passed {
+			report.Passed = false
+		}
+		report.Results = append(report.Results, result)
+	}
+
+	return report
+}
+
+// maxCheckOutput bounds how much check output is stored per command.
+const maxCheckOutput = 4096
+
+func shellCommand(command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", "/C", command)
+	}
+	return exec.Command("sh", "-c", command)
+}
+
+func tail(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[len(s)-n:]
 }
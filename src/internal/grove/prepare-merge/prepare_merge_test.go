@@ -1,6 +1,7 @@
 package preparemerge
 
 import (
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -9,11 +10,14 @@ import (
 
 	"github.com/kuchuk-borom-debbarma/GitGrove/src/internal/grove/initialize"
 	registerrepo "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/grove/register-repo"
+	"github.com/kuchuk-borom-debbarma/GitGrove/src/internal/testutil"
 	gitUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/git"
+	groveUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/grove"
 	"github.com/kuchuk-borom-debbarma/GitGrove/src/model"
 )
 
 func setupTestRepo(t *testing.T) string {
+	git := gitUtil.NewExecClient()
 	t.Helper()
 	dir, err := os.MkdirTemp("", "gg-test-pm")
 	if err != nil {
@@ -37,7 +41,7 @@ func setupTestRepo(t *testing.T) string {
 	// But actually subtree merge works fine.
 
 	// Initialize Grove
-	if err := initialize.Initialize(dir, false); err != nil {
+	if _, err := initialize.Initialize(git, dir, false); err != nil {
 		os.RemoveAll(dir)
 		t.Fatalf("Failed to initialize grove: %v", err)
 	}
@@ -46,6 +50,7 @@ func setupTestRepo(t *testing.T) string {
 }
 
 func TestPrepareMerge_FromOrphanBranch(t *testing.T) {
+	git := gitUtil.NewExecClient()
 	repoPath := setupTestRepo(t)
 	defer os.RemoveAll(repoPath)
 
@@ -69,7 +74,7 @@ func TestPrepareMerge_FromOrphanBranch(t *testing.T) {
 		Name: "service-a",
 		Path: "backend/serviceA",
 	}
-	if err := registerrepo.RegisterRepo([]model.GGRepo{newRepo}, repoPath); err != nil {
+	if _, err := registerrepo.RegisterRepo(git, []model.GGRepo{newRepo}, repoPath); err != nil {
 		t.Fatalf("RegisterRepo failed: %v", err)
 	}
 
@@ -104,7 +109,8 @@ func TestPrepareMerge_FromOrphanBranch(t *testing.T) {
 	// 5. Run PrepareMerge (detect context)
 	// We are on "gg/main/service-a".
 	// The function should detect trunk="main" and repo="service-a".
-	if err := PrepareMerge(repoPath, ""); err != nil {
+	result, err := PrepareMerge(git, repoPath, "")
+	if err != nil {
 		t.Fatalf("PrepareMerge failed: %v", err)
 	}
 
@@ -117,6 +123,10 @@ func TestPrepareMerge_FromOrphanBranch(t *testing.T) {
 	if !strings.HasPrefix(currentBranch, "gg/merge-prep/service-a/") {
 		t.Errorf("Expected current branch to start with gg/merge-prep/service-a/, got %s", currentBranch)
 	}
+	if result.Branch != currentBranch || result.Repo != "service-a" || result.Trunk != "main" ||
+		result.OrphanBranch != "gg/main/service-a" || result.Strategy != StrategyMerge || len(result.Commits) != 1 {
+		t.Errorf("Unexpected result: %+v", result)
+	}
 
 	// Check content of backend/serviceA/main.go in this new branch
 	// It should contain the update.
@@ -134,3 +144,340 @@ func TestPrepareMerge_FromOrphanBranch(t *testing.T) {
 		t.Errorf("Expected .gg/trunk to be removed, but it exists")
 	}
 }
+
+func TestPrepareMerge_RunsChecks(t *testing.T) {
+	git := gitUtil.NewExecClient()
+	repoPath := setupTestRepo(t)
+	defer os.RemoveAll(repoPath)
+
+	servicePath := filepath.Join(repoPath, "backend", "serviceA")
+	if err := os.MkdirAll(servicePath, 0755); err != nil {
+		t.Fatalf("Failed to create service dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(servicePath, "main.go"), []byte("package main"), 0644); err != nil {
+		t.Fatalf("Failed to create main.go: %v", err)
+	}
+	if err := gitUtil.Commit(repoPath, []string{"."}, "Add serviceA scaffold"); err != nil {
+		t.Fatalf("Failed to commit scaffold: %v", err)
+	}
+
+	// The first check runs in the repo directory, the second one fails.
+	newRepo := model.GGRepo{
+		Name:   "service-a",
+		Path:   "backend/serviceA",
+		Checks: []string{"test -f main.go", "exit 3"},
+	}
+	if _, err := registerrepo.RegisterRepo(git, []model.GGRepo{newRepo}, repoPath); err != nil {
+		t.Fatalf("RegisterRepo failed: %v", err)
+	}
+
+	// Something to integrate
+	gitUtil.Checkout(repoPath, "gg/main/service-a")
+	os.WriteFile(filepath.Join(repoPath, "util.go"), []byte("package main"), 0644)
+	if err := gitUtil.Commit(repoPath, []string{"util.go"}, "Add util"); err != nil {
+		t.Fatalf("Failed to commit in orphan: %v", err)
+	}
+
+	_, err := PrepareMerge(git, repoPath, "")
+	if err == nil || !strings.Contains(err.Error(), "pre-integration checks failed") {
+		t.Fatalf("Expected pre-integration check failure, got %v", err)
+	}
+
+	// The branch is left in place
+	currentBranch, _ := gitUtil.CurrentBranch(repoPath)
+	if !strings.HasPrefix(currentBranch, "gg/merge-prep/service-a/") {
+		t.Fatalf("Expected to stay on merge-prep branch, got %s", currentBranch)
+	}
+
+	// ...and flagged
+	report, err := groveUtil.LoadCheckReport(git, repoPath, currentBranch)
+	if err != nil || report == nil {
+		t.Fatalf("Expected a recorded check report, got %v (err: %v)", report, err)
+	}
+	if report.Passed {
+		t.Errorf("Expected report to be flagged as failed")
+	}
+	if len(report.Results) != 2 || !report.Results[0].Passed || report.Results[1].ExitCode != 3 {
+		t.Errorf("Unexpected check results: %+v", report.Results)
+	}
+}
+
+func TestPrepareMerge_ReplayStrategy(t *testing.T) {
+	client := gitUtil.NewExecClient()
+	repoPath := testutil.Workspace(t,
+		map[string]string{"backend/serviceA/main.go": "package main\n"},
+		model.GGRepo{Name: "service-a", Path: "backend/serviceA"},
+	)
+	servicePath := filepath.Join(repoPath, "backend", "serviceA")
+	mainGoPath := filepath.Join(servicePath, "main.go")
+
+	git := func(args ...string) string {
+		t.Helper()
+		return testutil.Git(t, repoPath, args...)
+	}
+
+	// Two orphan commits by another author
+	git("checkout", "gg/main/service-a")
+	os.WriteFile(filepath.Join(repoPath, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644)
+	git("add", "main.go")
+	git("-c", "user.name=Orphan Dev", "-c", "user.email=orphan@example.com", "commit", "--no-verify", "-m", "Add main", "--date=2001-02-03T04:05:06Z")
+	firstOrphan := git("rev-parse", "HEAD")
+	os.WriteFile(filepath.Join(repoPath, "util.go"), []byte("package main\n"), 0644)
+	git("add", "util.go")
+	git("commit", "--no-verify", "-m", "Add util", "-m", "Signed-off-by: Orphan Dev <orphan@example.com>")
+
+	if _, err := PrepareMergeWithStrategy(client, repoPath, "", StrategyReplay); err != nil {
+		t.Fatalf("PrepareMerge (replay) failed: %v", err)
+	}
+	prepBranch := git("symbolic-ref", "--short", "HEAD")
+
+	// Linear: exactly the two replayed commits, no merges
+	if merges := git("rev-list", "--merges", "main..HEAD"); merges != "" {
+		t.Errorf("Expected no merge commits, got %s", merges)
+	}
+	if count := git("rev-list", "--count", "main..HEAD"); count != "2" {
+		t.Fatalf("Expected 2 replayed commits, got %s", count)
+	}
+
+	// Authorship, date, message and trailer preserved on the first replayed commit
+	replayed := git("rev-list", "--reverse", "main..HEAD")
+	first := strings.Fields(replayed)[0]
+	if got := git("show", "-s", "--format=%an <%ae> %aI", first); got != "Orphan Dev <orphan@example.com> 2001-02-03T04:05:06+00:00" {
+		t.Errorf("Authorship not preserved: %s", got)
+	}
+	if got := git("show", "-s", "--format=%s", first); got != "Add main" {
+		t.Errorf("Message not preserved: %s", got)
+	}
+	if got := git("show", "-s", "--format=%(trailers:key=GG-Orphan-Commit,valueonly)", first); got != firstOrphan {
+		t.Errorf("Expected trailer %s, got %s", firstOrphan, got)
+	}
+
+	// Existing trailers stay in the trailer block next to the integration trailers
+	second := strings.Fields(replayed)[1]
+	if got := git("show", "-s", "--format=%(trailers:only,unfold)", second); !strings.HasPrefix(got, "Signed-off-by: Orphan Dev <orphan@example.com>\nGG-Repo: service-a\nGG-Orphan-Commit: ") {
+		t.Errorf("Expected one trailer block, got %q", got)
+	}
+
+	// Paths are translated
+	content, _ := os.ReadFile(mainGoPath)
+	if string(content) != "package main\n\nfunc main() {}\n" {
+		t.Errorf("Unexpected content: %q", content)
+	}
+	if _, err := os.Stat(filepath.Join(servicePath, "util.go")); err != nil {
+		t.Errorf("Expected util.go in repo path: %v", err)
+	}
+
+	// Integrate, then only the new orphan commit is replayed next time
+	git("checkout", "main")
+	git("merge", "--ff-only", prepBranch)
+	git("branch", "-D", prepBranch)
+	git("checkout", "gg/main/service-a")
+	os.WriteFile(filepath.Join(repoPath, "util.go"), []byte("package main\n\n// util\n"), 0644)
+	git("commit", "--no-verify", "-am", "Document util")
+
+	if _, err := PrepareMergeWithStrategy(client, repoPath, "", StrategyReplay); err != nil {
+		t.Fatalf("Second PrepareMerge (replay) failed: %v", err)
+	}
+	if count := git("rev-list", "--count", "main..HEAD"); count != "1" {
+		t.Errorf("Expected only the new commit to be replayed, got %s", count)
+	}
+}
+
+func TestPrepareMerge_TracksIntegrations(t *testing.T) {
+	client := gitUtil.NewExecClient()
+	repoPath := testutil.Workspace(t,
+		map[string]string{"backend/serviceA/main.go": "package main\n"},
+		model.GGRepo{Name: "service-a", Path: "backend/serviceA"},
+	)
+
+	git := func(args ...string) string {
+		t.Helper()
+		return testutil.Git(t, repoPath, args...)
+	}
+	orphan := "gg/main/service-a"
+
+	// Freshly registered: nothing pending
+	pending, err := groveUtil.PendingCommits(client, repoPath, "main", orphan, "service-a", "backend/serviceA")
+	if err != nil || len(pending) != 0 {
+		t.Fatalf("Expected no pending commits after registration, got %v (err: %v)", pending, err)
+	}
+	if _, err := PrepareMerge(client, repoPath, "service-a"); err == nil || !strings.Contains(err.Error(), "nothing to integrate") {
+		t.Fatalf("Expected 'nothing to integrate', got %v", err)
+	}
+
+	// One orphan commit, integrated with the merge strategy
+	git("checkout", orphan)
+	os.WriteFile(filepath.Join(repoPath, "util.go"), []byte("package main\n"), 0644)
+	git("add", "util.go")
+	git("commit", "--no-verify", "-m", "Add util")
+	orphanTip := git("rev-parse", "HEAD")
+
+	if _, err := PrepareMerge(client, repoPath, ""); err != nil {
+		t.Fatalf("PrepareMerge failed: %v", err)
+	}
+	prepBranch := git("symbolic-ref", "--short", "HEAD")
+	if got := git("show", "-s", "--format=%(trailers:key=GG-Orphan-Commit,valueonly)", "HEAD"); got != orphanTip {
+		t.Errorf("Expected merge commit trailer %s, got %q", orphanTip, got)
+	}
+
+	git("checkout", "main")
+	git("merge", "--ff-only", prepBranch)
+
+	record, err := groveUtil.LastIntegration(client, repoPath, "main", "service-a")
+	if err != nil || record == nil || record.OrphanCommit != orphanTip {
+		t.Fatalf("Expected last integration %s, got %+v (err: %v)", orphanTip, record, err)
+	}
+
+	// Only commits after the integration point are pending
+	git("checkout", orphan)
+	os.WriteFile(filepath.Join(repoPath, "util.go"), []byte("package main\n\n// util\n"), 0644)
+	git("commit", "--no-verify", "-am", "Document util")
+	newTip := git("rev-parse", "HEAD")
+
+	pending, err = groveUtil.PendingCommits(client, repoPath, "main", orphan, "service-a", "backend/serviceA")
+	if err != nil || len(pending) != 1 || pending[0] != newTip {
+		t.Errorf("Expected only %s pending, got %v (err: %v)", newTip, pending, err)
+	}
+}
+
+func TestPrepareMerge_ReplayRefusesMerges(t *testing.T) {
+	client := gitUtil.NewExecClient()
+	repoPath := testutil.Workspace(t,
+		map[string]string{"backend/serviceA/main.go": "package main\n"},
+		model.GGRepo{Name: "service-a", Path: "backend/serviceA"},
+	)
+	servicePath := filepath.Join(repoPath, "backend", "serviceA")
+
+	git := func(args ...string) string {
+		t.Helper()
+		return testutil.Git(t, repoPath, args...)
+	}
+
+	// A feature branch merged into the orphan branch
+	git("checkout", "gg/main/service-a")
+	git("checkout", "-b", "feature")
+	os.WriteFile(filepath.Join(repoPath, "util.go"), []byte("package main\n"), 0644)
+	git("add", "util.go")
+	git("commit", "--no-verify", "-m", "Add util")
+	git("checkout", "gg/main/service-a")
+	os.WriteFile(filepath.Join(repoPath, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644)
+	git("commit", "--no-verify", "-am", "Add main")
+	git("merge", "--no-ff", "--no-verify", "-m", "Merge feature", "feature")
+	mergeCommit := git("rev-parse", "HEAD")
+	git("checkout", "main")
+
+	pending, err := groveUtil.PendingCommits(client, repoPath, "main", "gg/main/service-a", "service-a", "backend/serviceA")
+	if err != nil || len(pending) != 3 || pending[2] != mergeCommit {
+		t.Fatalf("Expected both commits and the merge pending, got %v (err: %v)", pending, err)
+	}
+
+	_, err = PrepareMergeWithStrategy(client, repoPath, "service-a", StrategyReplay)
+	if err == nil || !strings.Contains(err.Error(), mergeCommit) {
+		t.Fatalf("Expected replay to refuse merge commit %s, got %v", mergeCommit, err)
+	}
+	if branches := git("branch", "--list", "gg/merge-prep/*"); branches != "" {
+		t.Errorf("Expected no merge-prep branch after a refused replay, got %s", branches)
+	}
+
+	result, err := PrepareMergeWithStrategy(client, repoPath, "service-a", StrategyMerge)
+	if err != nil {
+		t.Fatalf("PrepareMerge (merge) failed: %v", err)
+	}
+	if len(result.Commits) != 3 {
+		t.Errorf("Expected the merge commit among the integrated commits, got %v", result.Commits)
+	}
+	if _, err := os.Stat(filepath.Join(servicePath, "util.go")); err != nil {
+		t.Errorf("Expected the merged feature in the repo path: %v", err)
+	}
+}
+
+func TestPrepareMerge_KeepsTrunkEdits(t *testing.T) {
+	client := gitUtil.NewExecClient()
+	repoPath := testutil.Workspace(t,
+		map[string]string{"backend/serviceA/main.go": "package main\n", "backend/serviceA/config.txt": "port=80\n"},
+		model.GGRepo{Name: "service-a", Path: "backend/serviceA"},
+	)
+	servicePath := filepath.Join(repoPath, "backend", "serviceA")
+
+	git := func(args ...string) string {
+		t.Helper()
+		return testutil.Git(t, repoPath, args...)
+	}
+	read := func(name string) string {
+		content, _ := os.ReadFile(filepath.Join(servicePath, name))
+		return string(content)
+	}
+	orphan := "gg/main/service-a"
+
+	// The trunk edits the repo path after the orphan branch was created; the orphan edits another file
+	os.WriteFile(filepath.Join(servicePath, "config.txt"), []byte("port=8080\n"), 0644)
+	git("commit", "--no-verify", "-am", "Change port on trunk")
+	git("checkout", orphan)
+	os.WriteFile(filepath.Join(repoPath, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644)
+	git("commit", "--no-verify", "-am", "Add main")
+	orphanTip := git("rev-parse", "HEAD")
+
+	if _, err := PrepareMerge(client, repoPath, ""); err != nil {
+		t.Fatalf("PrepareMerge failed: %v", err)
+	}
+	if got := read("config.txt"); got != "port=8080\n" {
+		t.Errorf("Trunk edit lost by the integration: %q", got)
+	}
+	if got := read("main.go"); got != "package main\n\nfunc main() {}\n" {
+		t.Errorf("Orphan edit not integrated: %q", got)
+	}
+	if parents := git("show", "-s", "--format=%P", "HEAD"); !strings.HasSuffix(parents, " "+orphanTip) {
+		t.Errorf("Expected the orphan tip as second parent, got %s", parents)
+	}
+	prepBranch := git("symbolic-ref", "--short", "HEAD")
+	git("checkout", "main")
+	git("merge", "--ff-only", prepBranch)
+	git("branch", "-D", prepBranch)
+
+	// Second round: the recorded integration is the base, so both sides' new edits survive
+	os.WriteFile(filepath.Join(servicePath, "config.txt"), []byte("port=8080\nhost=local\n"), 0644)
+	git("commit", "--no-verify", "-am", "Add host on trunk")
+	git("checkout", orphan)
+	os.WriteFile(filepath.Join(repoPath, "util.go"), []byte("package main\n"), 0644)
+	git("add", "util.go")
+	git("commit", "--no-verify", "-m", "Add util")
+
+	if _, err := PrepareMerge(client, repoPath, ""); err != nil {
+		t.Fatalf("Second PrepareMerge failed: %v", err)
+	}
+	if got := read("config.txt"); got != "port=8080\nhost=local\n" {
+		t.Errorf("Trunk edit lost by the second integration: %q", got)
+	}
+	if got := read("util.go"); got != "package main\n" {
+		t.Errorf("Orphan edit not integrated: %q", got)
+	}
+	prepBranch = git("symbolic-ref", "--short", "HEAD")
+	git("checkout", "main")
+	git("merge", "--ff-only", prepBranch)
+	git("branch", "-D", prepBranch)
+
+	// Both sides change the same line: a conflict, left in progress for git commit
+	os.WriteFile(filepath.Join(servicePath, "main.go"), []byte("package app\n\nfunc main() {}\n"), 0644)
+	git("commit", "--no-verify", "-am", "Rename package on trunk")
+	git("checkout", orphan)
+	os.WriteFile(filepath.Join(repoPath, "main.go"), []byte("package service\n\nfunc main() {}\n"), 0644)
+	git("commit", "--no-verify", "-am", "Rename package on orphan")
+	conflictTip := git("rev-parse", "HEAD")
+
+	_, err := PrepareMerge(client, repoPath, "")
+	if !errors.Is(err, gitUtil.ErrMergeConflict) {
+		t.Fatalf("Expected a merge conflict, got %v", err)
+	}
+	if got := read("main.go"); !strings.Contains(got, "<<<<<<<") {
+		t.Errorf("Expected conflict markers, got %q", got)
+	}
+	os.WriteFile(filepath.Join(servicePath, "main.go"), []byte("package service\n\nfunc main() {}\n"), 0644)
+	git("add", "backend/serviceA/main.go")
+	git("commit", "--no-verify", "--no-edit")
+	if parents := git("show", "-s", "--format=%P", "HEAD"); !strings.HasSuffix(parents, " "+conflictTip) {
+		t.Errorf("Expected the concluded merge to have the orphan tip as parent, got %s", parents)
+	}
+	if got := git("show", "-s", "--format=%(trailers:key=GG-Orphan-Commit,valueonly)", "HEAD"); got != conflictTip {
+		t.Errorf("Expected the concluded merge to carry trailer %s, got %q", conflictTip, got)
+	}
+}
@@ -0,0 +1,81 @@
+package preparemerge
+
+import (
+	"fmt"
+
+	gitUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/git"
+	groveUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/grove"
+)
+
+// replayOrphanCommits re-creates the given orphan commits (oldest first) on top of the current branch,
+// translating their paths into repoPath. Authorship, author dates, and messages are preserved, and
+// each new commit carries GG-Repo / GG-Orphan-Commit trailers. Returns the number of commits created.
+func replayOrphanCommits(git gitUtil.GitClient, ggRepoPath string, repoName string, repoPath string, commits []string) (int, error) {
+	head, err := git.RevParse(ggRepoPath, "HEAD")
+	if err != nil {
+		return 0, err
+	}
+	headTree, err := git.RevParse(ggRepoPath, head+"^{tree}")
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	for _, commit := range commits {
+		info, err := git.GetCommitInfo(ggRepoPath, commit)
+		if err != nil {
+			return created, abortReplay(git, ggRepoPath, err)
+		}
+
+		// .gg/trunk is an orphan-only artifact and never lands on the trunk
+		patch, err := git.CommitPatch(ggRepoPath, commit, ".", ":(exclude).gg/trunk")
+		if err != nil {
+			return created, abortReplay(git, ggRepoPath, err)
+		}
+		if len(patch) == 0 {
+			continue
+		}
+
+		if err := git.ApplyPatchToIndex(ggRepoPath, patch, repoPath); err != nil {
+			return created, abortReplay(git, ggRepoPath, fmt.Errorf("failed to replay orphan commit %s: %w", commit, err))
+		}
+
+		tree, err := git.WriteTree(ggRepoPath)
+		if err != nil {
+			return created, abortReplay(git, ggRepoPath, err)
+		}
+		if tree == headTree {
+			// Change already present on trunk
+			continue
+		}
+
+		message, err := groveUtil.WithIntegrationTrailers(git, ggRepoPath, info.Message, repoName, info.SHA)
+		if err != nil {
+			return created, abortReplay(git, ggRepoPath, err)
+		}
+		newCommit, err := git.CommitTree(ggRepoPath, tree, []string{head}, message, info)
+		if err != nil {
+			return created, abortReplay(git, ggRepoPath, err)
+		}
+		if err := git.UpdateRef(ggRepoPath, "HEAD", newCommit); err != nil {
+			return created, abortReplay(git, ggRepoPath, err)
+		}
+
+		head, headTree = newCommit, tree
+		created++
+	}
+
+	// The index already matches HEAD; bring the working tree along.
+	if err := git.ResetHard(ggRepoPath, "HEAD"); err != nil {
+		return created, err
+	}
+	return created, nil
+}
+
+// abortReplay resets the index and working tree to the last successfully replayed commit.
+func abortReplay(git gitUtil.GitClient, ggRepoPath string, cause error) error {
+	if err := git.ResetHard(ggRepoPath, "HEAD"); err != nil {
+		return fmt.Errorf("%w (additionally, resetting to the last replayed commit failed: %v)", cause, err)
+	}
+	return cause
+}
@@ -18,6 +18,19 @@ func Description() string {
 		"- Moves files to root in the orphan branch"
 }
 
+// Result describes the repositories registered on a trunk.
+type Result struct {
+	Trunk string           `json:"trunk"`
+	Repos []RegisteredRepo `json:"repos"`
+}
+
+// RegisteredRepo is a registered repository and the orphan branch created for it.
+type RegisteredRepo struct {
+	Name         string `json:"name"`
+	Path         string `json:"path"`
+	OrphanBranch string `json:"orphan_branch"`
+}
+
 // RegisterRepo registers a folder as a "repo" within the GitGrove monorepo.
 //
 // Concept: The Split
@@ -33,22 +46,22 @@ func Description() string {
 // Limitation: Nested Repositories
 // Nested directories cannot be registered as repositories at this time.
 // Rules for this are yet to be clearly defined.
-func RegisterRepo(repos []model.GGRepo, ggRepoPath string) error {
+func RegisterRepo(git gitUtil.GitClient, repos []model.GGRepo, ggRepoPath string) (*Result, error) {
 	// Validate ggRepoPath (has .gg/gg.json and is git repo too)
-	if err := gitUtil.IsGitRepository(ggRepoPath); err != nil {
-		return err
+	if err := git.IsGitRepository(ggRepoPath); err != nil {
+		return nil, err
 	}
 
 	// Check if Grove is initialized
 	configPath := filepath.Join(ggRepoPath, ".gg", "gg.json")
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		return fmt.Errorf("gitgrove is not initialized in %s", ggRepoPath)
+		return nil, fmt.Errorf("%w in %s", groveUtil.ErrNotInitialized, ggRepoPath)
 	}
 
 	// Load repos from gg.json (needed for validation)
 	config, err := groveUtil.LoadConfig(ggRepoPath)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Clean paths to ensure consistency across validation, git operations, and config storage
@@ -58,26 +71,28 @@ func RegisterRepo(repos []model.GGRepo, ggRepoPath string) error {
 
 	// Validate BEFORE doing any git operations
 	if err := groveUtil.ValidateRepoRegistration(ggRepoPath, config, repos); err != nil {
-		return err
+		return nil, err
 	}
 
 	// Get current branch
-	currentBranch, err := gitUtil.CurrentBranch(ggRepoPath)
+	currentBranch, err := git.CurrentBranch(ggRepoPath)
 	if err != nil {
-		return fmt.Errorf("failed to get current branch: %w", err)
+		return nil, fmt.Errorf("failed to get current branch: %w", err)
 	}
 
 	// If all good, proceed creating the orphan branch
+	result := &Result{Trunk: currentBranch, Repos: []RegisteredRepo{}}
 	for _, repo := range repos {
 		branchName := fmt.Sprintf("gg/%s/%s", currentBranch, repo.Name)
-		if err := gitUtil.SubtreeSplit(ggRepoPath, repo.Path, branchName); err != nil {
-			return fmt.Errorf("failed to create subtree split for %s: %w", repo.Name, err)
+		if err := git.SubtreeSplit(ggRepoPath, repo.Path, branchName); err != nil {
+			return nil, fmt.Errorf("failed to create subtree split for %s: %w", repo.Name, err)
 		}
+		result.Repos = append(result.Repos, RegisteredRepo{Name: repo.Name, Path: repo.Path, OrphanBranch: branchName})
 	}
 
 	// ONLY if git operations succeed, update gg.json
 	if err := groveUtil.AddReposToConfig(ggRepoPath, repos); err != nil {
-		return fmt.Errorf("failed to update config after branch creation: %w", err)
+		return nil, fmt.Errorf("failed to update config after branch creation: %w", err)
 	}
 
 	// Create a commit for the configuration change
@@ -90,9 +105,9 @@ func RegisterRepo(repos []model.GGRepo, ggRepoPath string) error {
 	}
 	message := fmt.Sprintf("Register repo(s): %s", repoNames)
 
-	if err := gitUtil.Commit(ggRepoPath, []string{".gg/gg.json"}, message); err != nil {
-		return fmt.Errorf("failed to commit configuration change: %w", err)
+	if err := git.Commit(ggRepoPath, []string{".gg/gg.json"}, message); err != nil {
+		return nil, fmt.Errorf("failed to commit configuration change: %w", err)
 	}
 
-	return nil
+	return result, nil
 }
@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/kuchuk-borom-debbarma/GitGrove/src/internal/grove/initialize"
+	gitUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/git"
 	groveUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/grove"
 	"github.com/kuchuk-borom-debbarma/GitGrove/src/model"
 )
@@ -37,7 +38,7 @@ func setupTestRepo(t *testing.T) string {
 	cmd.Run()
 
 	// Initialize Grove
-	if err := initialize.Initialize(dir, false); err != nil {
+	if _, err := initialize.Initialize(gitUtil.NewExecClient(), dir, false); err != nil {
 		os.RemoveAll(dir)
 		t.Fatalf("Failed to initialize grove: %v", err)
 	}
@@ -46,6 +47,7 @@ func setupTestRepo(t *testing.T) string {
 }
 
 func TestRegisterRepo(t *testing.T) {
+	git := gitUtil.NewExecClient()
 	repoPath := setupTestRepo(t)
 	defer os.RemoveAll(repoPath)
 
@@ -77,7 +79,8 @@ func TestRegisterRepo(t *testing.T) {
 	}
 
 	// Register Repo
-	if err := RegisterRepo([]model.GGRepo{newRepo}, repoPath); err != nil {
+	result, err := RegisterRepo(git, []model.GGRepo{newRepo}, repoPath)
+	if err != nil {
 		t.Fatalf("RegisterRepo failed: %v", err)
 	}
 
@@ -114,6 +117,10 @@ func TestRegisterRepo(t *testing.T) {
 		expectedBranch = "gg/" + currentBranchStr + "/service-a"
 	}
 
+	if len(result.Repos) != 1 || result.Repos[0].OrphanBranch != expectedBranch || result.Trunk != currentBranchStr {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
 	cmd = exec.Command("git", "branch", "--list", expectedBranch)
 	cmd.Dir = repoPath
 	output, err := cmd.CombinedOutput()
@@ -126,6 +133,7 @@ func TestRegisterRepo(t *testing.T) {
 }
 
 func TestRegisterRepo_PathValidation(t *testing.T) {
+	git := gitUtil.NewExecClient()
 	repoPath := setupTestRepo(t)
 	defer os.RemoveAll(repoPath)
 
@@ -139,7 +147,7 @@ func TestRegisterRepo_PathValidation(t *testing.T) {
 		Path: "../outside",
 	}
 
-	err := RegisterRepo([]model.GGRepo{newRepo}, repoPath)
+	_, err := RegisterRepo(git, []model.GGRepo{newRepo}, repoPath)
 	if err == nil {
 		t.Fatal("Expected RegisterRepo to fail for path '../outside', but it succeeded")
 	}
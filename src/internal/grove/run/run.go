@@ -0,0 +1,210 @@
+package run
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kuchuk-borom-debbarma/GitGrove/src/internal/grove/affected"
+	gitUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/git"
+	groveUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/grove"
+)
+
+// Options select the repositories and control the execution.
+type Options struct {
+	Repos    []string  // only these repositories (all registered ones if empty)
+	Affected string    // only repositories changed in this "<base>..<head>" range
+	Parallel int       // maximum concurrent commands; 0 means the number of CPUs
+	Output   io.Writer // receives the commands' output, each line prefixed with "[repo] "
+}
+
+// Task is the outcome of the command in one repository.
+type Task struct {
+	Repo     string        `json:"repo"`
+	Path     string        `json:"path"`
+	ExitCode int           `json:"exit_code"` // -1 if the command could not be started
+	Duration time.Duration `json:"duration"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// Result lists the tasks in repository name order.
+type Result struct {
+	Command []string `json:"command"`
+	Tasks   []Task   `json:"tasks"`
+}
+
+// Passed reports whether the command succeeded in every repository.
+func (r *Result) Passed() bool {
+	for _, task := range r.Tasks {
+		if task.ExitCode != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Description returns a description of the task runner.
+func Description() string {
+	return "Run: Runs a command in the directory of each registered repository.\n" +
+		"- Selects all repositories, a list (--repos) or those changed in a range (--affected)\n" +
+		"- Runs in parallel (--parallel) with every output line prefixed by [repo]\n" +
+		"- Ends with a summary of exit codes and durations; GG_REPO and GG_REPO_PATH are set for the command"
+}
+
+// Run executes command (program and arguments, no shell) in each selected repository's directory.
+// A failing command does not stop the others; check Result.Passed.
+func Run(git gitUtil.GitClient, ggRepoPath string, opts Options, command []string) (*Result, error) {
+	if len(command) == 0 {
+		return nil, fmt.Errorf("no command given")
+	}
+	config, err := groveUtil.LoadWorkspaceConfig(git, ggRepoPath)
+	if err != nil {
+		return nil, err
+	}
+	names, err := selectRepos(git, ggRepoPath, config, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	output := opts.Output
+	if output == nil {
+		output = os.Stdout
+	}
+	parallel := opts.Parallel
+	if parallel <= 0 {
+		parallel = runtime.NumCPU()
+	}
+
+	result := &Result{Command: command, Tasks: make([]Task, len(names))}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, parallel)
+	for i, name := range names {
+		result.Tasks[i] = Task{Repo: name, Path: config.Repositories[name].Path}
+		wg.Add(1)
+		go func(task *Task) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			out := &prefixWriter{mu: &mu, out: output, prefix: "[" + task.Repo + "] "}
+			runTask(ggRepoPath, task, command, out)
+			out.Flush()
+		}(&result.Tasks[i])
+	}
+	wg.Wait()
+	return result, nil
+}
+
+// selectRepos returns the repositories to run in, sorted by name.
+func selectRepos(git gitUtil.GitClient, ggRepoPath string, config *groveUtil.GGConfig, opts Options) ([]string, error) {
+	selected := map[string]bool{}
+	if len(opts.Repos) > 0 {
+		for _, name := range opts.Repos {
+			if _, exists := config.Repositories[name]; !exists {
+				return nil, &groveUtil.RepoNotRegisteredError{Name: name}
+			}
+			selected[name] = true
+		}
+	} else {
+		for name := range config.Repositories {
+			selected[name] = true
+		}
+	}
+
+	if opts.Affected != "" {
+		base, head, err := affected.ParseRange(opts.Affected)
+		if err != nil {
+			return nil, err
+		}
+		changed, err := affected.Affected(git, ggRepoPath, base, head)
+		if err != nil {
+			return nil, err
+		}
+		isChanged := map[string]bool{}
+		for _, name := range changed.Names() {
+			isChanged[name] = true
+		}
+		for name := range selected {
+			if !isChanged[name] {
+				delete(selected, name)
+			}
+		}
+	}
+
+	names := make([]string, 0, len(selected))
+	for name := range selected {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func runTask(ggRepoPath string, task *Task, command []string, out io.Writer) {
+	start := time.Now()
+	defer func() { task.Duration = time.Since(start) }()
+
+	dir := filepath.Join(ggRepoPath, task.Path)
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		task.ExitCode = -1
+		task.Error = fmt.Sprintf("directory %s not found (run from the trunk)", task.Path)
+		fmt.Fprintln(out, task.Error)
+		return
+	}
+
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GG_REPO="+task.Repo, "GG_REPO_PATH="+task.Path)
+	cmd.Stdout, cmd.Stderr = out, out
+	if err := cmd.Run(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			task.ExitCode = exitErr.ExitCode()
+		} else {
+			task.ExitCode = -1
+			task.Error = err.Error()
+			fmt.Fprintln(out, task.Error)
+		}
+	}
+}
+
+// prefixWriter writes complete lines to out with a prefix. Lines of concurrent tasks never interleave.
+type prefixWriter struct {
+	mu     *sync.Mutex
+	out    io.Writer
+	prefix string
+	buf    []byte
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.writeLine(w.buf[:i+1])
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush writes a trailing line without newline.
+func (w *prefixWriter) Flush() {
+	if len(w.buf) > 0 {
+		w.writeLine(append(w.buf, '\n'))
+		w.buf = nil
+	}
+}
+
+func (w *prefixWriter) writeLine(line []byte) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	io.WriteString(w.out, w.prefix)
+	w.out.Write(line)
+}
@@ -0,0 +1,81 @@
+package run
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/kuchuk-borom-debbarma/GitGrove/src/internal/testutil"
+	gitUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/git"
+	"github.com/kuchuk-borom-debbarma/GitGrove/src/model"
+)
+
+func TestRun(t *testing.T) {
+	git := gitUtil.NewExecClient()
+	dir := testutil.Workspace(t,
+		map[string]string{"services/billing/file.txt": "billing", "services/search/file.txt": "search", "services/web/file.txt": "web"},
+		model.GGRepo{Name: "billing", Path: "services/billing"},
+		model.GGRepo{Name: "search", Path: "services/search"},
+		model.GGRepo{Name: "web", Path: "services/web"},
+	)
+
+	// Every repository, in its own directory, with GG_REPO set; search fails
+	var out bytes.Buffer
+	script := `cat file.txt; echo; echo "$GG_REPO_PATH"; test "$GG_REPO" != search`
+	result, err := Run(git, dir, Options{Parallel: 2, Output: &out}, []string{"sh", "-c", script})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(result.Tasks) != 3 || result.Passed() {
+		t.Fatalf("expected 3 tasks with one failure, got %+v", result.Tasks)
+	}
+	for _, task := range result.Tasks {
+		expected := 0
+		if task.Repo == "search" {
+			expected = 1
+		}
+		if task.ExitCode != expected {
+			t.Errorf("%s: expected exit code %d, got %d", task.Repo, expected, task.ExitCode)
+		}
+		if !strings.Contains(out.String(), "["+task.Repo+"] "+task.Repo+"\n") || !strings.Contains(out.String(), "["+task.Repo+"] "+task.Path+"\n") {
+			t.Errorf("expected prefixed output for %s, got:\n%s", task.Repo, out.String())
+		}
+	}
+
+	// --repos and --affected narrow the selection
+	gitUtil.Checkout(dir, "main")
+	exec.Command("git", "-C", dir, "checkout", "-q", "-b", "feature").Run()
+	os.WriteFile(filepath.Join(dir, "services", "web", "file.txt"), []byte("web2"), 0644)
+	os.WriteFile(filepath.Join(dir, "services", "search", "file.txt"), []byte("search2"), 0644)
+	gitUtil.CommitNoVerify(dir, []string{"."}, "Change web and search")
+	result, err = Run(git, dir, Options{Repos: []string{"billing", "web"}, Affected: "main..feature", Output: &out}, []string{"true"})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(result.Tasks) != 1 || result.Tasks[0].Repo != "web" || !result.Passed() {
+		t.Errorf("expected only web, got %+v", result.Tasks)
+	}
+
+	if _, err := Run(git, dir, Options{Repos: []string{"unknown"}}, []string{"true"}); err == nil {
+		t.Errorf("expected an error for an unregistered repository")
+	}
+	result, _ = Run(git, dir, Options{Repos: []string{"web"}, Output: &out}, []string{"definitely-not-a-command"})
+	if result.Tasks[0].ExitCode != -1 || result.Tasks[0].Error == "" {
+		t.Errorf("expected a start failure, got %+v", result.Tasks[0])
+	}
+}
+
+func TestPrefixWriter(t *testing.T) {
+	var out bytes.Buffer
+	w := &prefixWriter{mu: &sync.Mutex{}, out: &out, prefix: "[a] "}
+	w.Write([]byte("one\ntw"))
+	w.Write([]byte("o\nthree"))
+	w.Flush()
+	if out.String() != "[a] one\n[a] two\n[a] three\n" {
+		t.Errorf("unexpected output: %q", out.String())
+	}
+}
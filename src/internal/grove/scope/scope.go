@@ -0,0 +1,40 @@
+package scope
+
+import (
+	gitUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/git"
+	groveUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/grove"
+)
+
+// Description returns a description of the scope lock.
+func Description() string {
+	return "Scope: Locks commits to a single repository (Strict Mode).\n" +
+		"- Any commit touching files outside the repository is rejected, even root-only commits\n" +
+		"- Works on the trunk as well as on orphan and feature branches\n" +
+		"- Stays active until cleared with gg scope --clear"
+}
+
+// SetScope activates the scope lock for repoName after checking that it is registered.
+func SetScope(git gitUtil.GitClient, ggRepoPath string, repoName string) error {
+	config, err := groveUtil.LoadWorkspaceConfig(git, ggRepoPath)
+	if err != nil {
+		return err
+	}
+	if _, exists := config.Repositories[repoName]; !exists {
+		return &groveUtil.RepoNotRegisteredError{Name: repoName}
+	}
+	return groveUtil.SetContextScope(git, ggRepoPath, repoName)
+}
+
+// GetScope returns the active scope, or "" if none is set.
+func GetScope(git gitUtil.GitClient, ggRepoPath string) string {
+	repoName, err := groveUtil.GetContextScope(git, ggRepoPath)
+	if err != nil {
+		return ""
+	}
+	return repoName
+}
+
+// ClearScope removes the scope lock. Clearing an unset scope is not an error.
+func ClearScope(git gitUtil.GitClient, ggRepoPath string) error {
+	return groveUtil.ClearContextScope(git, ggRepoPath)
+}
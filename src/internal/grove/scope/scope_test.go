@@ -0,0 +1,85 @@
+package scope
+
+import (
+	"os/exec"
+	"testing"
+
+	gitUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/git"
+	groveUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/grove"
+	"github.com/kuchuk-borom-debbarma/GitGrove/src/model"
+)
+
+func TestScope(t *testing.T) {
+	git := gitUtil.NewExecClient()
+	dir := t.TempDir()
+	exec.Command("git", "init", dir).Run()
+
+	groveUtil.CreateGroveConfig(dir, false)
+	groveUtil.RegisterRepoInConfig(dir, []model.GGRepo{{Name: "service-a", Path: "services/a"}})
+
+	if err := SetScope(git, dir, "unknown"); err == nil {
+		t.Error("expected error for unregistered repository")
+	}
+	if got := GetScope(git, dir); got != "" {
+		t.Errorf("expected no scope, got %q", got)
+	}
+
+	if err := SetScope(git, dir, "service-a"); err != nil {
+		t.Fatalf("SetScope failed: %v", err)
+	}
+	if got := GetScope(git, dir); got != "service-a" {
+		t.Errorf("expected scope service-a, got %q", got)
+	}
+
+	// Leaving an orphan branch clears the checkout context but keeps the lock
+	groveUtil.ClearAllContext(git, dir)
+	if got := GetScope(git, dir); got != "service-a" {
+		t.Errorf("expected scope to survive ClearAllContext, got %q", got)
+	}
+
+	if err := ClearScope(git, dir); err != nil {
+		t.Fatalf("ClearScope failed: %v", err)
+	}
+	if got := GetScope(git, dir); got != "" {
+		t.Errorf("expected scope to be cleared, got %q", got)
+	}
+}
+
+// configClient keeps the git config in memory; any other git operation panics.
+type configClient struct {
+	gitUtil.GitClient
+	config map[string]string
+}
+
+func (c *configClient) SetLocalConfig(repoPath string, key string, value string) error {
+	c.config[key] = value
+	return nil
+}
+
+func (c *configClient) GetLocalConfig(repoPath string, key string) (string, error) {
+	return c.config[key], nil
+}
+
+func (c *configClient) UnsetLocalConfig(repoPath string, key string) error {
+	delete(c.config, key)
+	return nil
+}
+
+func TestScope_InjectedClient(t *testing.T) {
+	// No repository: gg.json is on disk and the client holds the config
+	dir := t.TempDir()
+	groveUtil.CreateGroveConfig(dir, false)
+	groveUtil.RegisterRepoInConfig(dir, []model.GGRepo{{Name: "service-a", Path: "services/a"}})
+	git := &configClient{config: map[string]string{}}
+
+	if err := SetScope(git, dir, "service-a"); err != nil {
+		t.Fatalf("SetScope failed: %v", err)
+	}
+	if git.config["gitgrove.context.scope"] != "service-a" || GetScope(git, dir) != "service-a" {
+		t.Errorf("expected the scope in the client's config, got %v", git.config)
+	}
+	ClearScope(git, dir)
+	if len(git.config) != 0 {
+		t.Errorf("expected the scope to be cleared, got %v", git.config)
+	}
+}
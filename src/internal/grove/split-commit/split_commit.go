@@ -0,0 +1,171 @@
+package splitcommit
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	gitUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/git"
+	groveUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/grove"
+)
+
+// Group kinds, in the order their commits are created.
+const (
+	KindRepo   = "repo"
+	KindShared = "shared"
+	KindRoot   = "root"
+)
+
+// Group is one commit created by SplitCommit.
+type Group struct {
+	Kind    string   `json:"kind"`
+	Name    string   `json:"name"` // repository or shared path name; empty for root
+	Files   []string `json:"files"`
+	Message string   `json:"message"`
+	SHA     string   `json:"sha,omitempty"` // set once the commit exists
+}
+
+// Description returns a description of the split commit process.
+func Description() string {
+	return "Split Commit: Turns a mixed index into atomic commits.\n" +
+		"- Groups the staged files by registered repository, shared path and root\n" +
+		"- Creates one commit per group, prefixing repository commits with [repo]\n" +
+		"- Restores the original index if any commit fails"
+}
+
+// Plan groups the staged files the way SplitCommit would commit them, without committing.
+// Root files declared neutral for a repository go with that repository; globally neutral
+// files go with the root group.
+func Plan(git gitUtil.GitClient, ggRepoPath string, message string) ([]Group, error) {
+	ggRepoPath = filepath.Clean(ggRepoPath)
+
+	config, err := groveUtil.LoadConfig(ggRepoPath)
+	if err != nil {
+		return nil, fmt.Errorf("split-commit only works on the trunk of a GitGrove workspace: %w", err)
+	}
+	staged, err := git.StagedPaths(ggRepoPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(staged) == 0 {
+		return nil, errors.New("nothing staged to split")
+	}
+
+	attribution := groveUtil.AttributeFiles(config, staged)
+	repoNames := attribution.RepoNames()
+
+	repoFiles := make(map[string][]string)
+	for _, name := range repoNames {
+		repoFiles[name] = append(repoFiles[name], attribution.Repos[name]...)
+	}
+	rootFiles := append([]string{}, attribution.Root...)
+	for _, file := range attribution.Neutral {
+		if owner := neutralOwner(config, repoNames, file); owner != "" {
+			repoFiles[owner] = append(repoFiles[owner], file)
+		} else {
+			rootFiles = append(rootFiles, file)
+		}
+	}
+
+	var groups []Group
+	for _, name := range repoNames {
+		groups = append(groups, Group{Kind: KindRepo, Name: name, Files: repoFiles[name], Message: prefixMessage(name, message)})
+	}
+	for _, name := range attribution.SharedNames() {
+		groups = append(groups, Group{Kind: KindShared, Name: name, Files: attribution.Shared[name], Message: message})
+	}
+	if len(rootFiles) > 0 {
+		groups = append(groups, Group{Kind: KindRoot, Files: rootFiles, Message: message})
+	}
+	return groups, nil
+}
+
+// SplitCommit commits the staged changes as one commit per group (see Plan), running the hooks
+// for each. An empty message requires edit, which opens the editor for every group. If any
+// commit fails, the branch is moved back to where it started and the original index is restored.
+func SplitCommit(git gitUtil.GitClient, ggRepoPath string, message string, edit bool) ([]Group, error) {
+	ggRepoPath = filepath.Clean(ggRepoPath)
+	if strings.TrimSpace(message) == "" && !edit {
+		return nil, errors.New("a commit message is required unless the editor is used")
+	}
+
+	groups, err := Plan(git, ggRepoPath, message)
+	if err != nil {
+		return nil, err
+	}
+
+	// Everything needed to put the index back exactly as it was
+	originalHead, err := git.RevParse(ggRepoPath, "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("split-commit needs an existing HEAD commit: %w", err)
+	}
+	stagedTree, err := git.WriteTree(ggRepoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	restore := func(cause error) ([]Group, error) {
+		if err := git.ResetSoft(ggRepoPath, originalHead); err != nil {
+			return nil, fmt.Errorf("%w (restoring HEAD to %.7s also failed: %v)", cause, originalHead, err)
+		}
+		if err := git.ReadTree(ggRepoPath, stagedTree); err != nil {
+			return nil, fmt.Errorf("%w (restoring the index from tree %s also failed: %v)", cause, stagedTree, err)
+		}
+		return nil, cause
+	}
+
+	// Start from an index that matches HEAD, then stage and commit one group at a time
+	if err := git.ResetIndex(ggRepoPath, "HEAD"); err != nil {
+		return restore(err)
+	}
+	for i := range groups {
+		group := &groups[i]
+		if err := git.ResetIndex(ggRepoPath, stagedTree, group.Files...); err != nil {
+			return restore(err)
+		}
+		if err := git.CommitStaged(ggRepoPath, group.Message, edit); err != nil {
+			return restore(fmt.Errorf("commit for %s failed, index restored: %w", describe(*group), err))
+		}
+		info, err := git.GetCommitInfo(ggRepoPath, "HEAD")
+		if err != nil {
+			return restore(err)
+		}
+		// The hooks or the editor may have changed the message
+		group.SHA, group.Message = info.SHA, strings.TrimSpace(info.Message)
+	}
+	return groups, nil
+}
+
+// neutralOwner returns the first of repoNames whose NeutralPaths match file, or "" when the file
+// is globally neutral (or matches no repository).
+func neutralOwner(config *groveUtil.GGConfig, repoNames []string, file string) string {
+	if groveUtil.MatchAnyPath(config.NeutralPaths, file) {
+		return ""
+	}
+	for _, name := range repoNames {
+		if groveUtil.MatchAnyPath(config.Repositories[name].NeutralPaths, file) {
+			return name
+		}
+	}
+	return ""
+}
+
+// prefixMessage prepends [repoName] unless the message already carries it.
+func prefixMessage(repoName string, message string) string {
+	prefix := fmt.Sprintf("[%s] ", repoName)
+	if strings.HasPrefix(message, prefix) {
+		return message
+	}
+	return prefix + message
+}
+
+func describe(group Group) string {
+	switch group.Kind {
+	case KindRepo:
+		return fmt.Sprintf("repository '%s'", group.Name)
+	case KindShared:
+		return fmt.Sprintf("shared path '%s'", group.Name)
+	}
+	return "root files"
+}
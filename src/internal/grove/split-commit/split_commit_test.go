@@ -0,0 +1,105 @@
+package splitcommit
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kuchuk-borom-debbarma/GitGrove/src/internal/testutil"
+	gitUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/git"
+)
+
+func setupWorkspace(t *testing.T) string {
+	dir := testutil.TwoRepoWorkspace(t)
+	// Replace the disabled hooks with test hooks
+	os.MkdirAll(filepath.Join(dir, ".git", "test-hooks"), 0755)
+	testutil.Git(t, dir, "config", "core.hooksPath", ".git/test-hooks")
+	return dir
+}
+
+func stageMixedChanges(dir string) {
+	os.WriteFile(filepath.Join(dir, "services", "repoA", "a.txt"), []byte("a2"), 0644)
+	os.Remove(filepath.Join(dir, "services", "repoB", "b.txt"))
+	os.WriteFile(filepath.Join(dir, "services", "repoB", "new b.txt"), []byte("b2"), 0644)
+	os.WriteFile(filepath.Join(dir, "README.md"), []byte("readme"), 0644)
+	exec.Command("git", "-C", dir, "add", "-A").Run()
+	// Unstaged edits must survive untouched
+	os.WriteFile(filepath.Join(dir, "services", "repoA", "a.txt"), []byte("a3 unstaged"), 0644)
+}
+
+func TestSplitCommit(t *testing.T) {
+	git := gitUtil.NewExecClient()
+	dir := setupWorkspace(t)
+	stageMixedChanges(dir)
+
+	groups, err := SplitCommit(git, dir, "Update things", false)
+	if err != nil {
+		t.Fatalf("SplitCommit failed: %v", err)
+	}
+	if len(groups) != 3 {
+		t.Fatalf("expected 3 groups, got %+v", groups)
+	}
+
+	expected := []struct {
+		subject string
+		files   []string
+	}{
+		{"[repoA] Update things", []string{"services/repoA/a.txt"}},
+		{"[repoB] Update things", []string{"services/repoB/b.txt", "services/repoB/new b.txt"}},
+		{"Update things", []string{"README.md"}},
+	}
+	subjects, _ := gitUtil.LogSubjects(dir, "HEAD~3", "HEAD")
+	for i, want := range expected {
+		if groups[i].Message != want.subject {
+			t.Errorf("group %d: expected message %q, got %q", i, want.subject, groups[i].Message)
+		}
+		files, _ := gitUtil.CommitFiles(dir, groups[i].SHA)
+		if strings.Join(files, ",") != strings.Join(want.files, ",") {
+			t.Errorf("group %d: expected files %v, got %v", i, want.files, files)
+		}
+	}
+	if len(subjects) != 3 {
+		t.Errorf("expected 3 new commits, got %v", subjects)
+	}
+
+	staged, _ := gitUtil.StagedPaths(dir)
+	if len(staged) != 0 {
+		t.Errorf("expected empty index, got %v", staged)
+	}
+	content, _ := os.ReadFile(filepath.Join(dir, "services", "repoA", "a.txt"))
+	if string(content) != "a3 unstaged" {
+		t.Errorf("expected unstaged edit to be kept, got %q", content)
+	}
+}
+
+func TestSplitCommit_RestoresIndexOnFailure(t *testing.T) {
+	git := gitUtil.NewExecClient()
+	dir := setupWorkspace(t)
+	stageMixedChanges(dir)
+
+	// The root commit is created last and is rejected
+	hook := "#!/bin/sh\ngit diff --cached --name-only | grep -q README.md && exit 1\nexit 0\n"
+	os.WriteFile(filepath.Join(dir, ".git", "test-hooks", "pre-commit"), []byte(hook), 0755)
+
+	headBefore, _ := gitUtil.RevParse(dir, "HEAD")
+	treeBefore, _ := gitUtil.WriteTree(dir)
+
+	if _, err := SplitCommit(git, dir, "Update things", false); err == nil || !strings.Contains(err.Error(), "root files") {
+		t.Fatalf("expected root commit failure, got: %v", err)
+	}
+
+	headAfter, _ := gitUtil.RevParse(dir, "HEAD")
+	treeAfter, _ := gitUtil.WriteTree(dir)
+	if headAfter != headBefore {
+		t.Errorf("expected HEAD to be restored to %s, got %s", headBefore, headAfter)
+	}
+	if treeAfter != treeBefore {
+		t.Errorf("expected index tree %s to be restored, got %s", treeBefore, treeAfter)
+	}
+	content, _ := os.ReadFile(filepath.Join(dir, "services", "repoA", "a.txt"))
+	if string(content) != "a3 unstaged" {
+		t.Errorf("expected unstaged edit to be kept, got %q", content)
+	}
+}
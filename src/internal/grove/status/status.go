@@ -0,0 +1,205 @@
+package status
+
+import (
+	"fmt"
+	"sort"
+
+	gitUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/git"
+	groveUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/grove"
+)
+
+// RepoStatus describes one registered repository.
+type RepoStatus struct {
+	Name            string                       `json:"name"`
+	Path            string                       `json:"path"`
+	OrphanBranch    string                       `json:"orphan_branch"`
+	OrphanExists    bool                         `json:"orphan_exists"`
+	Ahead           int                          `json:"ahead"`      // orphan commits not integrated into the trunk yet
+	Behind          int                          `json:"behind"`     // trunk commits to the repository's path not on the orphan branch
+	MergePrep       []string                     `json:"merge_prep"` // gg/merge-prep/<repo>/* branches not merged into the trunk
+	Checks          *groveUtil.CheckReport       `json:"checks"`     // checks recorded for the newest merge-prep branch, null if none
+	LastIntegration *groveUtil.IntegrationRecord `json:"last_integration"`
+	Current         bool                         `json:"current"` // the sticky context points at this repository
+	Dirty           bool                         `json:"dirty"`   // uncommitted changes belong to this repository
+	Error           string                       `json:"error,omitempty"`
+}
+
+// Context is the sticky context stored in git config (gitgrove.context.*).
+type Context struct {
+	Repo   string `json:"repo"`
+	Trunk  string `json:"trunk"`
+	Orphan string `json:"orphan"`
+	Scope  string `json:"scope"`
+}
+
+// Status is an overview of the workspace and every registered repository.
+type Status struct {
+	Branch       string       `json:"branch"`
+	Trunk        string       `json:"trunk"`
+	Context      Context      `json:"context"`
+	DirtyFiles   []string     `json:"dirty_files"`
+	StaleContext []string     `json:"stale_context"` // reasons the sticky context no longer matches the workspace
+	Repos        []RepoStatus `json:"repos"`
+}
+
+// Dirty reports whether the working tree has uncommitted changes.
+func (s *Status) Dirty() bool {
+	return len(s.DirtyFiles) > 0
+}
+
+// Description returns a description of the workspace status.
+func Description() string {
+	return "Status: Shows every registered repository at a glance.\n" +
+		"- Orphan branch, commits ahead of and behind the trunk, pending merge-prep branches and their check results\n" +
+		"- Last integration date and which repository the sticky context points at\n" +
+		"- Flags uncommitted changes and sticky context that no longer matches the workspace"
+}
+
+// GetStatus collects the status of the workspace at ggRepoPath. Per-repository failures (e.g. a
+// path that no longer exists on the trunk) are reported in RepoStatus.Error rather than failing.
+func GetStatus(git gitUtil.GitClient, ggRepoPath string) (*Status, error) {
+	branch, err := git.CurrentBranch(ggRepoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &Status{Branch: branch, DirtyFiles: []string{}, StaleContext: []string{}, Repos: []RepoStatus{}}
+	status.Context.Repo, _ = groveUtil.GetContextRepo(git, ggRepoPath)
+	status.Context.Trunk, _ = groveUtil.GetContextTrunk(git, ggRepoPath)
+	status.Context.Orphan, _ = groveUtil.GetContextOrphan(git, ggRepoPath)
+	status.Context.Scope, _ = groveUtil.GetContextScope(git, ggRepoPath)
+
+	trunk, contextRepo := groveUtil.ResolveRepoContext(git, ggRepoPath)
+	if trunk == "" {
+		trunk = branch
+	}
+	status.Trunk = trunk
+
+	config, err := groveUtil.LoadWorkspaceConfig(git, ggRepoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if status.DirtyFiles, err = git.DirtyPaths(ggRepoPath); err != nil {
+		return nil, err
+	}
+	// Without a trunk tree (orphan branches), the checked out tree is the context repository's.
+	// Merge-prep and other branches carrying the trunk tree are attributed by path.
+	dirtyRepos := map[string]bool{}
+	if groveUtil.HasTrunkTree(ggRepoPath) {
+		for repo := range groveUtil.AttributeFiles(config, status.DirtyFiles).Repos {
+			dirtyRepos[repo] = true
+		}
+	} else if contextRepo != "" && len(status.DirtyFiles) > 0 {
+		dirtyRepos[contextRepo] = true
+	}
+
+	names := make([]string, 0, len(config.Repositories))
+	for name := range config.Repositories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		repo := config.Repositories[name]
+		repoStatus := RepoStatus{
+			Name:         name,
+			Path:         repo.Path,
+			OrphanBranch: fmt.Sprintf("gg/%s/%s", trunk, name),
+			MergePrep:    []string{},
+			Current:      name == status.Context.Repo,
+			Dirty:        dirtyRepos[name],
+		}
+		if err := fillRepoStatus(git, ggRepoPath, trunk, &repoStatus); err != nil {
+			repoStatus.Error = err.Error()
+		}
+		status.Repos = append(status.Repos, repoStatus)
+	}
+
+	status.StaleContext = staleContext(git, ggRepoPath, config, status)
+	return status, nil
+}
+
+// fillRepoStatus computes the branch related fields of repoStatus.
+func fillRepoStatus(git gitUtil.GitClient, ggRepoPath string, trunk string, repoStatus *RepoStatus) error {
+	last, err := groveUtil.LastIntegration(git, ggRepoPath, trunk, repoStatus.Name)
+	if err != nil {
+		return err
+	}
+	repoStatus.LastIntegration = last
+
+	branches, err := git.ListBranches(ggRepoPath, "refs/heads/gg/merge-prep/"+repoStatus.Name+"/")
+	if err != nil {
+		return err
+	}
+	for _, branch := range branches {
+		if !git.IsAncestor(ggRepoPath, branch, trunk) {
+			repoStatus.MergePrep = append(repoStatus.MergePrep, branch)
+		}
+	}
+	// Branch names end in a timestamp, so the newest merge-prep branch sorts last
+	if len(repoStatus.MergePrep) > 0 {
+		newest := repoStatus.MergePrep[len(repoStatus.MergePrep)-1]
+		if repoStatus.Checks, err = groveUtil.LoadCheckReport(git, ggRepoPath, newest); err != nil {
+			return err
+		}
+	}
+
+	repoStatus.OrphanExists = branchExists(git, ggRepoPath, repoStatus.OrphanBranch)
+	if !repoStatus.OrphanExists {
+		return nil
+	}
+	// The subtree split rewrites the trunk history of the path; compute it once for both counts
+	split, err := git.SubtreeSplitRev(ggRepoPath, repoStatus.Path, trunk)
+	if err != nil {
+		return err
+	}
+	base, err := groveUtil.RecordedIntegrationBase(git, ggRepoPath, trunk, repoStatus.OrphanBranch, repoStatus.Name)
+	if err != nil {
+		return err
+	}
+	if base == "" {
+		base = groveUtil.SplitIntegrationBase(git, ggRepoPath, split, repoStatus.OrphanBranch)
+	}
+	ahead, err := groveUtil.PendingCommitsSince(git, ggRepoPath, trunk, repoStatus.OrphanBranch, base)
+	if err != nil {
+		return err
+	}
+	behind, err := groveUtil.TrunkOnlyCommitsOfSplit(git, ggRepoPath, split, repoStatus.OrphanBranch)
+	if err != nil {
+		return err
+	}
+	repoStatus.Ahead, repoStatus.Behind = len(ahead), len(behind)
+	return nil
+}
+
+// staleContext explains why the sticky context does not match the workspace (empty if it does).
+func staleContext(git gitUtil.GitClient, ggRepoPath string, config *groveUtil.GGConfig, status *Status) []string {
+	reasons := []string{}
+	context := status.Context
+	if context.Repo != "" {
+		if _, exists := config.Repositories[context.Repo]; !exists {
+			reasons = append(reasons, fmt.Sprintf("context repository '%s' is not registered", context.Repo))
+		}
+	}
+	if context.Trunk != "" && !branchExists(git, ggRepoPath, context.Trunk) {
+		reasons = append(reasons, fmt.Sprintf("context trunk '%s' does not exist", context.Trunk))
+	}
+	if context.Orphan != "" && !branchExists(git, ggRepoPath, context.Orphan) {
+		reasons = append(reasons, fmt.Sprintf("context orphan branch '%s' does not exist", context.Orphan))
+	}
+	if (context.Repo != "" || context.Orphan != "") && status.Branch == context.Trunk {
+		reasons = append(reasons, fmt.Sprintf("context is still set on the trunk '%s' (gg trunk clears it)", context.Trunk))
+	}
+	if context.Scope != "" {
+		if _, exists := config.Repositories[context.Scope]; !exists {
+			reasons = append(reasons, fmt.Sprintf("scope '%s' is not a registered repository", context.Scope))
+		}
+	}
+	return reasons
+}
+
+func branchExists(git gitUtil.GitClient, ggRepoPath string, branch string) bool {
+	_, err := git.RevParse(ggRepoPath, "refs/heads/"+branch)
+	return err == nil
+}
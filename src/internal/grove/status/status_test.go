@@ -0,0 +1,97 @@
+package status
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	preparemerge "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/grove/prepare-merge"
+	"github.com/kuchuk-borom-debbarma/GitGrove/src/internal/testutil"
+	gitUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/git"
+	groveUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/grove"
+)
+
+func TestGetStatus(t *testing.T) {
+	git := gitUtil.NewExecClient()
+	dir := testutil.TwoRepoWorkspace(t)
+
+	// repoA: one orphan commit, left on an unmerged merge-prep branch
+	gitUtil.Checkout(dir, "gg/main/repoA")
+	os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a2"), 0644)
+	gitUtil.CommitNoVerify(dir, []string{"a.txt"}, "Update a")
+	prep, err := preparemerge.PrepareMerge(git, dir, "")
+	if err != nil {
+		t.Fatalf("PrepareMerge failed: %v", err)
+	}
+
+	report := &groveUtil.CheckReport{Repo: "repoA", Branch: prep.Branch, Passed: true,
+		Results: []groveUtil.CheckResult{{Command: "go test ./...", Passed: true}}}
+	if err := groveUtil.SaveCheckReport(git, dir, report); err != nil {
+		t.Fatalf("SaveCheckReport failed: %v", err)
+	}
+
+	// repoB: a trunk commit not on its orphan branch, plus an uncommitted change
+	gitUtil.Checkout(dir, "main")
+	os.WriteFile(filepath.Join(dir, "services", "repoB", "b.txt"), []byte("b2"), 0644)
+	gitUtil.CommitNoVerify(dir, []string{"."}, "Update b on trunk")
+	os.WriteFile(filepath.Join(dir, "services", "repoB", "new.txt"), []byte("new"), 0644)
+
+	result, err := GetStatus(git, dir)
+	if err != nil {
+		t.Fatalf("GetStatus failed: %v", err)
+	}
+	if result.Trunk != "main" || len(result.Repos) != 2 {
+		t.Fatalf("unexpected status: %+v", result)
+	}
+
+	repoA, repoB := result.Repos[0], result.Repos[1]
+	if !repoA.OrphanExists || repoA.Ahead != 1 || repoA.Behind != 0 || len(repoA.MergePrep) != 1 || repoA.MergePrep[0] != prep.Branch {
+		t.Errorf("unexpected repoA status: %+v", repoA)
+	}
+	if repoA.Checks == nil || repoA.Checks.Summary() != "checks passed (1/1)" {
+		t.Errorf("expected the checks of the merge-prep branch, got %+v", repoA.Checks)
+	}
+	if !repoA.Current || repoA.Dirty || repoA.LastIntegration != nil {
+		t.Errorf("expected repoA to be the clean, never integrated context repository: %+v", repoA)
+	}
+	if repoB.Ahead != 0 || repoB.Behind != 1 || !repoB.Dirty || repoB.Current || repoB.Checks != nil {
+		t.Errorf("unexpected repoB status: %+v", repoB)
+	}
+	if !result.Dirty() {
+		t.Errorf("expected a dirty working tree")
+	}
+	// The context from PrepareMerge was never cleared, but we are back on the trunk
+	if len(result.StaleContext) != 1 || !strings.Contains(result.StaleContext[0], "still set on the trunk") {
+		t.Errorf("expected stale context on the trunk, got %v", result.StaleContext)
+	}
+
+	// Merging the merge-prep branch integrates repoA
+	if err := gitUtil.Merge(dir, prep.Branch); err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	groveUtil.ClearAllContext(git, dir)
+	result, err = GetStatus(git, dir)
+	if err != nil {
+		t.Fatalf("GetStatus failed: %v", err)
+	}
+	repoA = result.Repos[0]
+	if repoA.Ahead != 0 || repoA.Behind != 0 || len(repoA.MergePrep) != 0 || repoA.Checks != nil || repoA.LastIntegration == nil || repoA.Current {
+		t.Errorf("expected repoA to be integrated: %+v", repoA)
+	}
+	if len(result.StaleContext) != 0 {
+		t.Errorf("expected no stale context, got %v", result.StaleContext)
+	}
+
+	// A branch carrying the trunk tree attributes dirty files by path, whatever the sticky context says
+	testutil.Git(t, dir, "checkout", "-q", "-b", "feature")
+	groveUtil.SetContextRepo(git, dir, "repoA")
+	groveUtil.SetContextTrunk(git, dir, "main")
+	result, err = GetStatus(git, dir)
+	if err != nil {
+		t.Fatalf("GetStatus failed: %v", err)
+	}
+	if repoA, repoB := result.Repos[0], result.Repos[1]; repoA.Dirty || !repoB.Dirty {
+		t.Errorf("expected only repoB to be dirty, got repoA %v and repoB %v", repoA.Dirty, repoB.Dirty)
+	}
+}
@@ -3,7 +3,6 @@ package sync
 import (
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"time"
 
@@ -14,10 +13,10 @@ import (
 // ResetOrphanToTrunk resets the current orphan branch to match the trunk's subtree state.
 // WARNING: This is a destructive operation that effectively deletes local changes in the orphan branch
 // and replaces them with the trunk's version.
-func ResetOrphanToTrunk(rootPath, currentBranch, trunkBranch, repoName string) error {
+func ResetOrphanToTrunk(git gitUtil.GitClient, rootPath, currentBranch, trunkBranch, repoName string) error {
 	// 0. Resolve Current Branch (if missing)
 	if currentBranch == "" {
-		cb, err := gitUtil.CurrentBranch(rootPath)
+		cb, err := git.CurrentBranch(rootPath)
 		if err != nil {
 			return fmt.Errorf("failed to determine current branch: %w", err)
 		}
@@ -27,56 +26,50 @@ func ResetOrphanToTrunk(rootPath, currentBranch, trunkBranch, repoName string) e
 	// 1. Identify Source Trunk
 	targetTrunk := trunkBranch
 	if targetTrunk == "" {
-		stickyTrunk, err := groveUtil.GetContextTrunk(rootPath)
-		if err != nil || stickyTrunk == "" {
+		contextTrunk, _ := groveUtil.ResolveRepoContext(git, rootPath)
+		if contextTrunk == "" {
 			return fmt.Errorf("unknown trunk branch. Please checkout repo again from TUI to set context")
 		}
-		targetTrunk = stickyTrunk
+		targetTrunk = contextTrunk
+	}
+
+	// 1.1. Identify Repository (if missing)
+	if repoName == "" {
+		_, contextRepo := groveUtil.ResolveRepoContext(git, rootPath)
+		if contextRepo == "" {
+			return fmt.Errorf("unknown repository. Please checkout repo again from TUI to set context")
+		}
+		repoName = contextRepo
 	}
 
 	// 2. Load Config from Trunk to find Repo Path
-	config, err := groveUtil.LoadConfigFromGitRef(rootPath, targetTrunk)
+	config, err := groveUtil.LoadConfigFromGitRef(git, rootPath, targetTrunk)
 	if err != nil {
 		return fmt.Errorf("failed to load config from trunk '%s': %w", targetTrunk, err)
 	}
 
 	repoConfig, exists := config.Repositories[repoName]
 	if !exists {
-		return fmt.Errorf("repository '%s' not found in trunk configuration", repoName)
+		return &groveUtil.RepoNotRegisteredError{Name: repoName}
 	}
 
 	repoRelPath := repoConfig.Path
 
 	// Pre-flight checks to provide better errors
 	// 1. Verify trunk exists
-	if _, err := gitUtil.RepoRoot(); err == nil { // quick check if git repo
-		cmd := exec.Command("git", "rev-parse", "--verify", targetTrunk)
-		cmd.Dir = rootPath
-		if err := cmd.Run(); err != nil {
+	if _, err := git.RepoRoot(); err == nil { // quick check if git repo
+		if _, err := git.RevParse(rootPath, targetTrunk); err != nil {
 			return fmt.Errorf("trunk branch '%s' does not exist locally. Try 'git fetch origin %s:%s'", targetTrunk, targetTrunk, targetTrunk)
 		}
 	}
 
 	// 2. Verify path exists in trunk and is a directory
-	// git ls-tree -d <trunk> <path>
-	// We use execCommand to capture output for debugging
-	lsCmd := exec.Command("git", "ls-tree", targetTrunk, repoRelPath)
-	lsCmd.Dir = rootPath
-	lsOut, err := lsCmd.CombinedOutput()
-
-	// Log the ls-tree output to helps us see what git sees
-	if err != nil || len(lsOut) == 0 {
-		return fmt.Errorf("path '%s' not found in trunk '%s' (git ls-tree output: %s). Is it committed?", repoRelPath, targetTrunk, string(lsOut))
+	objectType, err := git.ObjectType(rootPath, targetTrunk, repoRelPath)
+	if err != nil {
+		return fmt.Errorf("path '%s' not found in trunk '%s' (%v). Is it committed?", repoRelPath, targetTrunk, err)
 	}
-
-	// Check if it is a directory (tree)
-	// Output format: <mode> tree <hash> <path>
-	lsOutStr := string(lsOut)
-	_ = os.WriteFile(filepath.Join(rootPath, "gitgrove_debug_ls.log"), lsOut, 0644) // Check log
-
-	if len(lsOutStr) < 6 || lsOutStr[7:11] != "tree" { // simplified check, usually "040000 tree ..."
-		// It might be a blob?
-		return fmt.Errorf("path '%s' in trunk '%s' is not a directory. Git subtree requires a directory. (git check: %s)", repoRelPath, targetTrunk, lsOutStr)
+	if objectType != "tree" {
+		return fmt.Errorf("path '%s' in trunk '%s' is not a directory (%s). Git subtree requires a directory.", repoRelPath, targetTrunk, objectType)
 	}
 
 	// 3. Create a temporary branch with the latest subtree state from trunk
@@ -86,7 +79,7 @@ func ResetOrphanToTrunk(rootPath, currentBranch, trunkBranch, repoName string) e
 	// This avoids "fatal: prefix does not exist" errors when running from orphan branch.
 
 	// A. Checkout Trunk
-	if err := gitUtil.Checkout(rootPath, targetTrunk); err != nil {
+	if err := git.Checkout(rootPath, targetTrunk); err != nil {
 		return fmt.Errorf("failed to checkout trunk '%s' for sync operation: %w", targetTrunk, err)
 	}
 
@@ -96,9 +89,9 @@ func ResetOrphanToTrunk(rootPath, currentBranch, trunkBranch, repoName string) e
 
 	// B. Split Subtree (now we are in trunk)
 	// We use "HEAD" explicitly since we have checked out the trunk.
-	if err := gitUtil.SubtreeSplitFrom(rootPath, repoRelPath, "HEAD", tempSyncBranch); err != nil {
+	if err := git.SubtreeSplitFrom(rootPath, repoRelPath, "HEAD", tempSyncBranch); err != nil {
 		// Try to return to orphan even if failed
-		_ = gitUtil.Checkout(rootPath, currentBranch)
+		_ = git.Checkout(rootPath, currentBranch)
 
 		// Log full error
 		fullError := fmt.Sprintf("Command: git subtree split -P %s -b %s %s\nError: %v", repoRelPath, tempSyncBranch, targetTrunk, err)
@@ -107,31 +100,58 @@ func ResetOrphanToTrunk(rootPath, currentBranch, trunkBranch, repoName string) e
 	}
 
 	// C. Return to Orphan Branch
-	if err := gitUtil.Checkout(rootPath, currentBranch); err != nil {
+	if err := git.Checkout(rootPath, currentBranch); err != nil {
 		return fmt.Errorf("failed to return to orphan branch '%s' after split: %w", currentBranch, err)
 	}
 
 	// Clean immediately to remove artifacts from trunk visitation (e.g. vekku-server folder)
-	if err := gitUtil.Clean(rootPath); err != nil {
+	if err := git.Clean(rootPath); err != nil {
 		return fmt.Errorf("post-checkout clean failed: %w", err)
 	}
 
 	// Defer cleanup of temp branch
 	defer func() {
-		gitUtil.DeleteBranch(rootPath, tempSyncBranch, true)
+		git.DeleteBranch(rootPath, tempSyncBranch, true)
 	}()
 
 	// 4. Hard Reset the orphan branch to the temp branch commit
 	// This replaces "Merge" to ensure exact match and no conflicts.
-	if err := gitUtil.ResetHard(rootPath, tempSyncBranch); err != nil {
+	if err := git.ResetHard(rootPath, tempSyncBranch); err != nil {
 		return fmt.Errorf("reset to trunk failed: %w", err)
 	}
 
 	// 5. Clean untracked files again
 	// Switching to trunk might have left untracked files that shouldn't be here in orphan mode.
-	if err := gitUtil.Clean(rootPath); err != nil {
+	if err := git.Clean(rootPath); err != nil {
 		return fmt.Errorf("post-sync clean failed: %w", err)
 	}
 
 	return nil
 }
+
+// UnintegratedCommits lists the commits on the repository's orphan branch that have not been integrated into
+// the trunk yet, i.e. the commits a ResetOrphanToTrunk would discard. Empty arguments are inferred from context.
+func UnintegratedCommits(git gitUtil.GitClient, rootPath, trunkBranch, repoName string) ([]string, error) {
+	contextTrunk, contextRepo := groveUtil.ResolveRepoContext(git, rootPath)
+	if trunkBranch == "" {
+		trunkBranch = contextTrunk
+	}
+	if repoName == "" {
+		repoName = contextRepo
+	}
+	if trunkBranch == "" || repoName == "" {
+		return nil, fmt.Errorf("unknown trunk or repository. Please checkout repo again from TUI to set context")
+	}
+
+	config, err := groveUtil.LoadConfigFromGitRef(git, rootPath, trunkBranch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config from trunk '%s': %w", trunkBranch, err)
+	}
+	repoConfig, exists := config.Repositories[repoName]
+	if !exists {
+		return nil, &groveUtil.RepoNotRegisteredError{Name: repoName}
+	}
+
+	orphanBranch := fmt.Sprintf("gg/%s/%s", trunkBranch, repoName)
+	return groveUtil.PendingCommits(git, rootPath, trunkBranch, orphanBranch, repoName, repoConfig.Path)
+}
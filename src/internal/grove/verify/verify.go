@@ -0,0 +1,142 @@
+package verify
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kuchuk-borom-debbarma/GitGrove/src/internal/grove/hooks"
+	gitUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/git"
+	groveUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/grove"
+)
+
+// Violation kinds reported by Verify.
+const (
+	KindAtomicity = "atomicity" // the commit breaks the atomic commit rules
+	KindPrefix    = "prefix"    // the [repo] prefix does not match what prepare-commit-msg would produce
+	KindMessage   = "message"   // the message breaks the repository's commit rules
+)
+
+// Violation is a single rule broken by a commit.
+type Violation struct {
+	SHA     string `json:"sha"`
+	Subject string `json:"subject"`
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+}
+
+// Result is the outcome of verifying a commit range.
+type Result struct {
+	Range      string      `json:"range"`
+	Commits    int         `json:"commits"` // commits in the range
+	Checked    int         `json:"checked"` // commits the rules were applied to (orphan history is skipped)
+	Violations []Violation `json:"violations"`
+}
+
+// Passed reports whether no violations were found.
+func (r *Result) Passed() bool {
+	return len(r.Violations) == 0
+}
+
+// Description returns a description of the range verification.
+func Description() string {
+	return "Verify: Checks a commit range against the GitGrove rules (for CI).\n" +
+		"- Applies the pre-commit atomic rules to every commit, using the gg.json of that commit (or of the base)\n" +
+		"- Reports [repo] prefixes that do not match the files and repository commit-rule violations\n" +
+		"- Catches commits that bypassed the local hooks with --no-verify"
+}
+
+// Verify checks every commit in rangeSpec ("<base>..<head>"). With useBaseConfig, the gg.json of the
+// base is applied to all commits, so a range cannot relax the rules it is checked against; otherwise
+// each commit is checked against its own gg.json. Commits without a gg.json are skipped either way.
+func Verify(git gitUtil.GitClient, ggRepoPath string, rangeSpec string, useBaseConfig bool) (*Result, error) {
+	base, head, ok := strings.Cut(rangeSpec, "..")
+	if !ok || base == "" || head == "" || strings.HasPrefix(head, ".") {
+		return nil, fmt.Errorf("invalid range %q: expected <base>..<head>", rangeSpec)
+	}
+
+	var baseConfig *groveUtil.GGConfig
+	if useBaseConfig {
+		config, err := groveUtil.LoadConfigFromGitRef(git, ggRepoPath, base)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load gg.json from %s: %w", base, err)
+		}
+		baseConfig = config
+	}
+
+	commits, err := git.RevList(ggRepoPath, "--reverse", base+".."+head)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{Range: rangeSpec, Commits: len(commits), Violations: []Violation{}}
+	for _, sha := range commits {
+		violations, checked, err := verifyCommit(git, ggRepoPath, sha, baseConfig)
+		if err != nil {
+			return nil, err
+		}
+		if checked {
+			result.Checked++
+		}
+		result.Violations = append(result.Violations, violations...)
+	}
+	return result, nil
+}
+
+// verifyCommit applies the rules to a single commit. Merges are checked for their own edits (see
+// hooks.InspectCommit). checked is false for commits without a gg.json (orphan history brought in by
+// integrations), even with a base config: their paths are relative to the repository, not the trunk.
+func verifyCommit(git gitUtil.GitClient, root string, sha string, config *groveUtil.GGConfig) (violations []Violation, checked bool, err error) {
+	if exists, _ := git.FileExistsInBranch(root, sha, ".gg/gg.json"); !exists {
+		return nil, false, nil
+	}
+	if config == nil {
+		if config, err = groveUtil.LoadConfigFromGitRef(git, root, sha); err != nil {
+			return nil, false, err
+		}
+	}
+
+	change, err := hooks.InspectCommit(git, root, sha, config)
+	if err != nil {
+		return nil, false, err
+	}
+	info, err := git.GetCommitInfo(root, sha)
+	if err != nil {
+		return nil, false, err
+	}
+	message := strings.TrimSpace(info.Message)
+	subject := strings.SplitN(message, "\n", 2)[0]
+
+	add := func(kind string, text string) {
+		violations = append(violations, Violation{SHA: sha, Subject: subject, Kind: kind, Message: text})
+	}
+
+	// 1. Atomic commit rules, as in the pre-commit and pre-push hooks
+	if err := hooks.CheckAtomicity(config, change.Own); err != nil {
+		add(KindAtomicity, err.Error())
+	}
+
+	attribution := change.Attribution
+	if len(change.Files) == 0 || change.Merge || hooks.IsGeneratedMessage(message) {
+		return violations, true, nil
+	}
+
+	// 2. The prefix prepare-commit-msg would have added
+	owner := ""
+	if len(attribution.Repos) == 1 && !attribution.AffectsRoot() {
+		owner = attribution.RepoNames()[0]
+	}
+	for _, text := range hooks.CheckRepoPrefix(config, owner, subject) {
+		add(KindPrefix, text)
+	}
+	if config.RepoAwareContextMessage && owner != "" && hooks.RepoPrefix(subject) == "" {
+		add(KindPrefix, fmt.Sprintf("message is missing the [%s] prefix", owner))
+	}
+
+	// 3. Per-repository conventions, as in the commit-msg hook
+	if repo, ok := config.Repositories[owner]; ok && owner != "" {
+		for _, text := range hooks.CheckCommitMessage(repo.CommitRules, message) {
+			add(KindMessage, text)
+		}
+	}
+	return violations, true, nil
+}
@@ -0,0 +1,138 @@
+package verify
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	preparemerge "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/grove/prepare-merge"
+	"github.com/kuchuk-borom-debbarma/GitGrove/src/internal/testutil"
+	gitUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/git"
+	groveUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/grove"
+)
+
+func TestVerify(t *testing.T) {
+	git := gitUtil.NewExecClient()
+	dir := testutil.TwoRepoWorkspace(t)
+	base, _ := gitUtil.RevParse(dir, "HEAD")
+
+	// 1. Clean commit -> no violations
+	os.WriteFile(filepath.Join(dir, "services", "repoA", "a.txt"), []byte("a2"), 0644)
+	gitUtil.CommitNoVerify(dir, []string{"."}, "[repoA] Update a")
+	result, err := Verify(git, dir, base+"..HEAD", false)
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if !result.Passed() || result.Checked != 1 {
+		t.Fatalf("expected one clean commit, got %+v", result)
+	}
+
+	// 2. Mixed commit and mismatched prefix -> violations with SHAs
+	os.WriteFile(filepath.Join(dir, "services", "repoA", "a.txt"), []byte("a3"), 0644)
+	os.WriteFile(filepath.Join(dir, "services", "repoB", "b.txt"), []byte("b3"), 0644)
+	gitUtil.CommitNoVerify(dir, []string{"."}, "Touch both")
+	mixed, _ := gitUtil.RevParse(dir, "HEAD")
+	os.WriteFile(filepath.Join(dir, "services", "repoB", "b.txt"), []byte("b4"), 0644)
+	gitUtil.CommitNoVerify(dir, []string{"."}, "[repoA] Actually repoB")
+	mislabeled, _ := gitUtil.RevParse(dir, "HEAD")
+
+	result, err = Verify(git, dir, base+"..HEAD", false)
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if len(result.Violations) != 2 {
+		t.Fatalf("expected 2 violations, got %+v", result.Violations)
+	}
+	if v := result.Violations[0]; v.SHA != mixed || v.Kind != KindAtomicity {
+		t.Errorf("expected atomicity violation on %s, got %+v", mixed, v)
+	}
+	if v := result.Violations[1]; v.SHA != mislabeled || v.Kind != KindPrefix || !strings.Contains(v.Message, "belong to repository 'repoB'") {
+		t.Errorf("expected prefix violation on %s, got %+v", mislabeled, v)
+	}
+
+	// 3. A range cannot relax the rules when checked against the base config
+	config, _ := groveUtil.LoadConfig(dir)
+	config.NeutralPaths = []string{"docs"}
+	data, _ := json.MarshalIndent(config, "", "  ")
+	os.WriteFile(filepath.Join(dir, ".gg", "gg.json"), data, 0644)
+	gitUtil.CommitNoVerify(dir, []string{".gg/gg.json"}, "Relax rules")
+	os.MkdirAll(filepath.Join(dir, "docs"), 0755)
+	os.WriteFile(filepath.Join(dir, "docs", "a.md"), []byte("docs"), 0644)
+	os.WriteFile(filepath.Join(dir, "services", "repoA", "a.txt"), []byte("a5"), 0644)
+	gitUtil.CommitNoVerify(dir, []string{"."}, "[repoA] With docs")
+
+	result, _ = Verify(git, dir, "HEAD~1..HEAD", false)
+	if !result.Passed() {
+		t.Errorf("expected commit to pass with its own config, got %+v", result.Violations)
+	}
+	result, _ = Verify(git, dir, base+"..HEAD", true)
+	found := false
+	for _, v := range result.Violations {
+		if strings.Contains(v.Subject, "With docs") && v.Kind == KindAtomicity {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected base config to reject the docs commit, got %+v", result.Violations)
+	}
+
+	// 4. Merges: bringing in other repos' history is fine, editing two repos while merging is not
+	mergeBase, _ := gitUtil.RevParse(dir, "HEAD")
+	testutil.Git(t, dir, "checkout", "-q", "-b", "other")
+	os.WriteFile(filepath.Join(dir, "services", "repoB", "b.txt"), []byte("b6"), 0644)
+	gitUtil.CommitNoVerify(dir, []string{"."}, "[repoB] Update b")
+	testutil.Git(t, dir, "checkout", "-q", "-")
+	os.WriteFile(filepath.Join(dir, "services", "repoA", "a.txt"), []byte("a6"), 0644)
+	gitUtil.CommitNoVerify(dir, []string{"."}, "[repoA] Update a again")
+	testutil.Git(t, dir, "merge", "-q", "--no-ff", "--no-verify", "-m", "Merge other", "other")
+
+	result, _ = Verify(git, dir, mergeBase+"..HEAD", false)
+	if !result.Passed() || result.Checked != 3 {
+		t.Errorf("expected the clean merge to pass, got %+v", result)
+	}
+	testutil.Git(t, dir, "reset", "-q", "--hard", "HEAD~1")
+	testutil.Git(t, dir, "merge", "-q", "--no-ff", "--no-commit", "other")
+	os.WriteFile(filepath.Join(dir, "services", "repoA", "a.txt"), []byte("evil"), 0644)
+	os.WriteFile(filepath.Join(dir, "services", "repoB", "b.txt"), []byte("evil"), 0644)
+	gitUtil.CommitNoVerify(dir, []string{"."}, "Merge other")
+	evil, _ := gitUtil.RevParse(dir, "HEAD")
+
+	result, _ = Verify(git, dir, mergeBase+"..HEAD", false)
+	if len(result.Violations) != 1 || result.Violations[0].SHA != evil || result.Violations[0].Kind != KindAtomicity {
+		t.Errorf("expected an atomicity violation on the evil merge, got %+v", result.Violations)
+	}
+
+	if _, err := Verify(git, dir, "HEAD", false); err == nil {
+		t.Error("expected an error for a range without ..")
+	}
+}
+
+func TestVerify_SkipsIntegratedOrphanHistory(t *testing.T) {
+	git := gitUtil.NewExecClient()
+	dir := testutil.TwoRepoWorkspace(t)
+	base, _ := gitUtil.RevParse(dir, "HEAD")
+
+	// Orphan commits are relative to the repository and carry no gg.json
+	testutil.Git(t, dir, "checkout", "-q", "gg/main/repoA")
+	os.WriteFile(filepath.Join(dir, "new.txt"), []byte("new"), 0644)
+	gitUtil.CommitNoVerify(dir, []string{"new.txt"}, "[repoA] Add new file")
+	prep, err := preparemerge.PrepareMerge(git, dir, "")
+	if err != nil {
+		t.Fatalf("PrepareMerge failed: %v", err)
+	}
+	testutil.Git(t, dir, "checkout", "-q", "main")
+	testutil.Git(t, dir, "merge", "-q", "--no-ff", "--no-verify", "-m", "Merge integration", prep.Branch)
+
+	for _, useBaseConfig := range []bool{false, true} {
+		result, err := Verify(git, dir, base+"..HEAD", useBaseConfig)
+		if err != nil {
+			t.Fatalf("Verify failed: %v", err)
+		}
+		// Only the subtree merge and the merge into main: the split and orphan commits are skipped
+		if !result.Passed() || result.Checked != 2 || result.Commits != 4 {
+			t.Errorf("expected the orphan commit to be skipped (base config %v), got %+v", useBaseConfig, result)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+// Package testutil holds the git and workspace fixtures shared by the
+// GitGrove tests.
+package testutil
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kuchuk-borom-debbarma/GitGrove/src/internal/grove/initialize"
+	registerrepo "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/grove/register-repo"
+	gitUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/git"
+	"github.com/kuchuk-borom-debbarma/GitGrove/src/model"
+)
+
+// GitRepo creates an empty repository on main with a test identity.
+func GitRepo(t testing.TB) string {
+	t.Helper()
+	dir := t.TempDir()
+	Git(t, dir, "init", "--initial-branch=main")
+	Git(t, dir, "config", "user.email", "test@example.com")
+	Git(t, dir, "config", "user.name", "Test User")
+	return dir
+}
+
+// Git runs git in dir and returns its trimmed output, failing the test on error.
+func Git(t testing.TB, dir string, args ...string) string {
+	t.Helper()
+	out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// WriteFile writes content to the slash-separated path under dir, creating
+// parent directories as needed.
+func WriteFile(t testing.TB, dir, path, content string) {
+	t.Helper()
+	full := filepath.Join(dir, filepath.FromSlash(path))
+	if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Workspace initializes GitGrove in a fresh repository, commits files on the
+// trunk and registers repos. The installed hooks are switched off through
+// core.hooksPath so test commits see the tree as CI would.
+func Workspace(t testing.TB, files map[string]string, repos ...model.GGRepo) string {
+	t.Helper()
+	git := gitUtil.NewExecClient()
+	dir := GitRepo(t)
+	if _, err := initialize.Initialize(git, dir, false); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	Git(t, dir, "config", "core.hooksPath", "/dev/null")
+	if len(files) > 0 {
+		for path, content := range files {
+			WriteFile(t, dir, path, content)
+		}
+		if err := gitUtil.CommitNoVerify(dir, []string{"."}, "Add services"); err != nil {
+			t.Fatalf("Commit failed: %v", err)
+		}
+	}
+	if len(repos) > 0 {
+		if _, err := registerrepo.RegisterRepo(git, repos, dir); err != nil {
+			t.Fatalf("RegisterRepo failed: %v", err)
+		}
+	}
+	return dir
+}
+
+// TwoRepoWorkspace is the common fixture: repoA and repoB registered under
+// services/, holding a.txt and b.txt.
+func TwoRepoWorkspace(t testing.TB) string {
+	t.Helper()
+	return Workspace(t,
+		map[string]string{"services/repoA/a.txt": "a", "services/repoB/b.txt": "b"},
+		model.GGRepo{Name: "repoA", Path: "services/repoA"},
+		model.GGRepo{Name: "repoB", Path: "services/repoB"},
+	)
+}
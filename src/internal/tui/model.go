@@ -4,8 +4,13 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/textinput"
+	installhooks "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/grove/install-hooks"
+	preparemerge "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/grove/prepare-merge"
+	"github.com/kuchuk-borom-debbarma/GitGrove/src/internal/grove/scope"
+	"github.com/kuchuk-borom-debbarma/GitGrove/src/internal/grove/status"
 	gitUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/git"
 	groveUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/grove"
 )
@@ -39,32 +44,49 @@ type Model struct {
 	path             string
 	textInput        textinput.Model
 	descriptions     map[string]string
-	registerName     string   // Name for the new repo
-	isOrphan         bool     // True if in orphan branch
-	orphanRepoName   string   // Name of repo if in orphan branch
-	trunkBranch      string   // Name of trunk branch if in orphan branch
-	orphanBranch     string   // The original orphan branch name (e.g. gg/main/service-a)
-	suggestions      []string // Autocompletion suggestions
-	suggestionCursor int      // Selected suggestion index
-	buildTime        string   // Build time of the binary
+	registerName     string         // Name for the new repo
+	isOrphan         bool           // True if in orphan branch
+	orphanRepoName   string         // Name of repo if in orphan branch
+	trunkBranch      string         // Name of trunk branch if in orphan branch
+	orphanBranch     string         // The original orphan branch name (e.g. gg/main/service-a)
+	suggestions      []string       // Autocompletion suggestions
+	suggestionCursor int            // Selected suggestion index
+	buildTime        string         // Build time of the binary
+	scope            string         // Active scope lock (gg scope), empty if none
+	hooksWarning     string         // Set when GitGrove hooks are missing or outdated
+	status           *status.Status // Workspace overview shown by View Repos
+	checkInfo        checkInfoCache // Check results of the current branch, reloaded when the report changes
+	git              gitUtil.GitClient
 }
 
-func InitialModel(buildTime string) Model {
+// checkInfoCache keeps the formatted check report of the current branch. The report path is resolved
+// (a git call) only when the workspace or branch changes; afterwards the report is re-read only when
+// its file changes, e.g. when a prepare-merge running elsewhere finishes its checks.
+type checkInfoCache struct {
+	key     string
+	path    string
+	loaded  bool
+	modTime time.Time // of the report file, zero when it does not exist
+	info    string
+}
+
+func InitialModel(buildTime string, git gitUtil.GitClient) Model {
 	cwd, _ := os.Getwd()
+	cwd = groveUtil.WorkspaceRoot(git, cwd)
 
 	initialState := StateInit
 	var repoInfo string
 	var isOrphan bool
 	var orphanRepoName, trunkBranch, orphanName, currentBranch string // Hoisted currentBranch
 
-	// Check initialization status (returns error if initialized)
-	errInit := groveUtil.IsGroveInitialized(cwd)
+	// Check initialization status
+	initStatus, _ := groveUtil.IsGroveInitialized(git, cwd)
 
-	if errInit != nil {
+	if initStatus.Initialized() {
 		initialState = StateIdle
 		// Determine context: Trunk or Orphan?
 		var err error
-		currentBranch, err = gitUtil.CurrentBranch(cwd)
+		currentBranch, err = git.CurrentBranch(cwd)
 		if err == nil {
 			// Check for Orphan Pattern: gg/<trunk>/<repoName>
 			// We can use the same logic as in grove_util or prepare_merge
@@ -97,7 +119,7 @@ func InitialModel(buildTime string) Model {
 	}
 
 	// Try to overwrite with sticky context if available (more reliable for deep branches)
-	if stickyOrphan, err := groveUtil.GetContextOrphan(cwd); err == nil && stickyOrphan != "" {
+	if stickyOrphan, err := groveUtil.GetContextOrphan(git, cwd); err == nil && stickyOrphan != "" {
 		orphanName = stickyOrphan
 		// If we are deep, isOrphan might be false from prefix check, but sticky says we are in an orphan workflow.
 		// So strict prefix check `if len(branch) > 3` above might be failing for `feat/foo`.
@@ -105,10 +127,10 @@ func InitialModel(buildTime string) Model {
 		if !isOrphan {
 			isOrphan = true
 			// We need to fetch other context too if not already set
-			if stickyRepo, err := groveUtil.GetContextRepo(cwd); err == nil {
+			if stickyRepo, err := groveUtil.GetContextRepo(git, cwd); err == nil {
 				orphanRepoName = stickyRepo
 			}
-			if stickyTrunk, err := groveUtil.GetContextTrunk(cwd); err == nil {
+			if stickyTrunk, err := groveUtil.GetContextTrunk(git, cwd); err == nil {
 				trunkBranch = stickyTrunk
 			}
 			repoInfo = fmt.Sprintf("Feature Branch: %s (Root: %s)", currentBranch, orphanRepoName)
@@ -139,7 +161,7 @@ func InitialModel(buildTime string) Model {
 
 			// If we are deep in a feature branch (i.e., current branch != original orphan branch),
 			// offer direct return to orphan.
-			currentBranch, _ := gitUtil.CurrentBranch(cwd)
+			currentBranch, _ := git.CurrentBranch(cwd)
 			// We have orphanName from earlier logic, but we need the full orphan branch name.
 			// Earlier we set `m.orphanBranch`? No, we set top-level variable.
 			// Let's ensure we use the local variable `orphanName` which we read from config.
@@ -178,6 +200,12 @@ func InitialModel(buildTime string) Model {
 		descriptions:     descriptions,
 		suggestionCursor: -1,
 		buildTime:        buildTime,
+		git:              git,
+	}
+
+	m.scope = scope.GetScope(git, cwd)
+	if initialState == StateIdle {
+		m.hooksWarning = getHooksWarning(git, cwd)
 	}
 
 	// Run an initial refresh to ensure all logic is consistent
@@ -194,10 +222,11 @@ func (m *Model) Refresh() {
 	cwd := m.path // Use current tracked path
 	if cwd == "" {
 		cwd, _ = os.Getwd()
+		cwd = groveUtil.WorkspaceRoot(m.git, cwd)
 	}
 
 	// Re-check init
-	if err := groveUtil.IsGroveInitialized(cwd); err == nil {
+	if initStatus, err := groveUtil.IsGroveInitialized(m.git, cwd); err != nil || !initStatus.Initialized() {
 		// Not initialized or error, maybe we lost init?
 		// If we were initialized, this is a big change.
 		// For safety, let's primarily check branch/context if we are already initialized.
@@ -205,7 +234,7 @@ func (m *Model) Refresh() {
 	}
 
 	// We are initialized. Check branch context.
-	currentBranch, err := gitUtil.CurrentBranch(cwd)
+	currentBranch, err := m.git.CurrentBranch(cwd)
 	if err != nil {
 		return
 	}
@@ -232,21 +261,26 @@ func (m *Model) Refresh() {
 	}
 
 	// Sticky context check
-	if stickyOrphan, err := groveUtil.GetContextOrphan(cwd); err == nil && stickyOrphan != "" {
+	if stickyOrphan, err := groveUtil.GetContextOrphan(m.git, cwd); err == nil && stickyOrphan != "" {
 		orphanName = stickyOrphan
 		if !isOrphan {
 			isOrphan = true
-			if stickyRepo, err := groveUtil.GetContextRepo(cwd); err == nil {
+			if stickyRepo, err := groveUtil.GetContextRepo(m.git, cwd); err == nil {
 				orphanRepoName = stickyRepo
 			}
-			if stickyTrunk, err := groveUtil.GetContextTrunk(cwd); err == nil {
+			if stickyTrunk, err := groveUtil.GetContextTrunk(m.git, cwd); err == nil {
 				trunkBranch = stickyTrunk
 			}
 			repoInfo = fmt.Sprintf("Feature Branch: %s (Root: %s)", currentBranch, orphanRepoName)
 		}
 	}
 
+	// Pre-integration check results (only recorded for prepare-merge branches)
+	m.refreshCheckInfo(cwd, currentBranch)
+	repoInfo += m.checkInfo.info
+
 	// Update model
+	m.scope = scope.GetScope(m.git, cwd)
 	m.isOrphan = isOrphan
 	m.repoInfo = repoInfo
 	if isOrphan {
@@ -273,3 +307,59 @@ func (m *Model) Refresh() {
 		}
 	}
 }
+
+// getHooksWarning reports missing or outdated hooks, e.g. in a fresh clone where Initialize never ran.
+func getHooksWarning(git gitUtil.GitClient, cwd string) string {
+	statuses, err := installhooks.Status(git, cwd)
+	if err != nil {
+		return ""
+	}
+	var missing, outdated []string
+	for _, status := range statuses {
+		switch {
+		case !status.Managed:
+			missing = append(missing, status.Name)
+		case status.Outdated():
+			outdated = append(outdated, status.Name)
+		}
+	}
+	switch {
+	case len(missing) > 0:
+		return fmt.Sprintf("Hooks missing: %s (run gg hooks install)", strings.Join(missing, ", "))
+	case len(outdated) > 0:
+		return fmt.Sprintf("Hooks outdated: %s (run gg hooks upgrade)", strings.Join(outdated, ", "))
+	}
+	return ""
+}
+
+// prepareMergeSummary renders the outcome of PrepareMerge for the status line.
+func prepareMergeSummary(result *preparemerge.Result) string {
+	summary := fmt.Sprintf("Success: %s created (%d commit(s) from %s)", result.Branch, len(result.Commits), result.OrphanBranch)
+	if result.Checks != nil {
+		summary += ", " + result.Checks.Summary()
+	}
+	if len(result.Warnings) > 0 {
+		summary += "; warnings: " + strings.Join(result.Warnings, "; ")
+	}
+	return summary
+}
+
+// refreshCheckInfo updates the cached check report of branch (see checkInfoCache).
+func (m *Model) refreshCheckInfo(cwd string, branch string) {
+	if key := cwd + "\x00" + branch; m.checkInfo.key != key {
+		path, _ := groveUtil.CheckReportPath(m.git, cwd, branch)
+		m.checkInfo = checkInfoCache{key: key, path: path}
+	}
+	if m.checkInfo.path == "" {
+		return
+	}
+	var modTime time.Time
+	if info, err := os.Stat(m.checkInfo.path); err == nil {
+		modTime = info.ModTime()
+	}
+	if m.checkInfo.loaded && modTime.Equal(m.checkInfo.modTime) {
+		return
+	}
+	m.checkInfo.loaded, m.checkInfo.modTime = true, modTime
+	m.checkInfo.info = getCheckInfo(m.checkInfo.path)
+}
@@ -13,8 +13,8 @@ import (
 	"github.com/kuchuk-borom-debbarma/GitGrove/src/internal/grove/initialize"
 	preparemerge "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/grove/prepare-merge"
 	registerrepo "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/grove/register-repo"
+	"github.com/kuchuk-borom-debbarma/GitGrove/src/internal/grove/status"
 	grovesync "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/grove/sync"
-	gitUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/git"
 	groveUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/grove"
 	"github.com/kuchuk-borom-debbarma/GitGrove/src/model"
 )
@@ -133,48 +133,43 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if path == "" {
 					path, _ = os.Getwd()
 				}
+				path = groveUtil.WorkspaceRoot(m.git, path)
 
 				// Validate if it is a GitGrove repo
-				err := groveUtil.IsGroveInitialized(path)
+				initStatus, err := groveUtil.IsGroveInitialized(m.git, path)
 
 				if err != nil {
-					// Check if error message confirms initialization
-					if err.Error() == fmt.Sprintf("gitgrove is already initialized in %s", path) ||
-						(len(err.Error()) > 30 && err.Error()[:31] == "gitgrove is already initialized") ||
-						strings.Contains(err.Error(), "sticky context") {
-						// Success
-						m.path = path
-
-						// Get context info
-						currentBranch, _ := gitUtil.CurrentBranch(path)
-						if len(currentBranch) > 3 && currentBranch[:3] == "gg/" {
-							m.isOrphan = true
-							parts := strings.Split(currentBranch, "/")
-							if len(parts) >= 3 {
-								orphanRepoName := parts[len(parts)-1]
-								trunkBranch := strings.Join(parts[1:len(parts)-1], "/")
-								m.repoInfo = fmt.Sprintf("Orphan Branch: %s (Trunk: %s)", orphanRepoName, trunkBranch)
-								m.orphanRepoName = orphanRepoName
-								m.trunkBranch = trunkBranch
-							} else {
-								m.repoInfo = fmt.Sprintf("Orphan Branch: %s", currentBranch)
-							}
-							m.choices = []string{"Prepare Merge", "Reset to Trunk", "Return to Trunk", "Quit"}
+					m.err = err
+				} else if initStatus.Initialized() {
+					// Success
+					m.path = path
+
+					// Get context info
+					currentBranch, _ := m.git.CurrentBranch(path)
+					if len(currentBranch) > 3 && currentBranch[:3] == "gg/" {
+						m.isOrphan = true
+						parts := strings.Split(currentBranch, "/")
+						if len(parts) >= 3 {
+							orphanRepoName := parts[len(parts)-1]
+							trunkBranch := strings.Join(parts[1:len(parts)-1], "/")
+							m.repoInfo = fmt.Sprintf("Orphan Branch: %s (Trunk: %s)", orphanRepoName, trunkBranch)
+							m.orphanRepoName = orphanRepoName
+							m.trunkBranch = trunkBranch
 						} else {
-							m.isOrphan = false
-							m.repoInfo = getTrunkContextInfo(path, currentBranch)
-							m.choices = []string{"View Repos", "Register Repo", "Checkout Repo Branch", "Quit"}
+							m.repoInfo = fmt.Sprintf("Orphan Branch: %s", currentBranch)
 						}
-
-						m.state = StateIdle
-						m.cursor = 0
-						m.err = nil
+						m.choices = []string{"Prepare Merge", "Reset to Trunk", "Return to Trunk", "Quit"}
 					} else {
-						// Some other error
-						m.err = err
+						m.isOrphan = false
+						m.repoInfo = getTrunkContextInfo(path, currentBranch)
+						m.choices = []string{"View Repos", "Register Repo", "Checkout Repo Branch", "Quit"}
 					}
+
+					m.state = StateIdle
+					m.cursor = 0
+					m.err = nil
 				} else {
-					// err == nil means not initialized
+					// Not initialized
 					m.err = fmt.Errorf("path '%s' is not a GitGrove repository", path)
 				}
 				return m, nil
@@ -246,8 +241,13 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if path == "" {
 					path, _ = os.Getwd()
 				}
+				path = groveUtil.WorkspaceRoot(m.git, path)
 
-				if err := groveUtil.IsGroveInitialized(path); err != nil {
+				initStatus, err := groveUtil.IsGroveInitialized(m.git, path)
+				if err == nil {
+					err = initStatus.AlreadyInitializedError(path)
+				}
+				if err != nil {
 					m.err = err
 					return m, nil
 				}
@@ -294,7 +294,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case tea.KeyMsg:
 			switch msg.String() {
 			case "y", "Y":
-				if err := initialize.Initialize(m.path, true); err != nil {
+				if _, err := initialize.Initialize(m.git, m.path, true); err != nil {
 					m.err = err
 				} else {
 					m.repoInfo = "GitGrove Initialized at " + m.path
@@ -305,7 +305,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				return m, nil
 			case "n", "N":
-				if err := initialize.Initialize(m.path, false); err != nil {
+				if _, err := initialize.Initialize(m.git, m.path, false); err != nil {
 					m.err = err
 				} else {
 					m.repoInfo = "GitGrove Initialized at " + m.path
@@ -340,10 +340,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					if m.isOrphan {
 						// Pass m.orphanRepoName. If empty, PrepareMerge might fail or try sticky context again.
 						// But m.orphanRepoName should be populated if isOrphan is true.
-						if err := preparemerge.PrepareMerge(m.path, m.orphanRepoName); err != nil {
+						if result, err := preparemerge.PrepareMerge(m.git, m.path, m.orphanRepoName); err != nil {
 							m.err = err
 						} else {
-							m.repoInfo = "Success: Prepare-merge branch created"
+							m.repoInfo = prepareMergeSummary(result)
 							m.state = StateIdle
 							// We might want to refresh model state here as checkouts happened?
 							// The user is now on prepare-merge branch.
@@ -365,6 +365,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					}
 					m.state = StateConfirmReset
 					m.repoInfo = "WARNING: This will discard ALL local changes in this branch. Are you sure? (y/n)"
+					if pending, err := grovesync.UnintegratedCommits(m.git, m.path, m.trunkBranch, m.orphanRepoName); err == nil && len(pending) > 0 {
+						m.repoInfo = fmt.Sprintf("WARNING: This will discard ALL local changes in this branch, including %d commit(s) not yet integrated into trunk. Are you sure? (y/n)", len(pending))
+					}
 					return m, nil
 
 				case "Return to Orphan Branch":
@@ -372,7 +375,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						m.err = fmt.Errorf("unknown orphan branch context")
 						return m, nil
 					}
-					if err := gitUtil.Checkout(m.path, m.orphanBranch); err != nil {
+					if err := m.git.Checkout(m.path, m.orphanBranch); err != nil {
 						m.err = fmt.Errorf("failed to checkout orphan branch: %v", err)
 					} else {
 						// We don't clear context because we are still in the orphan context!
@@ -403,11 +406,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						m.err = fmt.Errorf("unknown trunk branch")
 						return m, nil
 					}
-					if err := gitUtil.Checkout(m.path, m.trunkBranch); err != nil {
+					if err := m.git.Checkout(m.path, m.trunkBranch); err != nil {
 						m.err = fmt.Errorf("failed to checkout trunk: %v", err)
 					} else {
 						// Clear sticky context
-						groveUtil.ClearAllContext(m.path)
+						groveUtil.ClearAllContext(m.git, m.path)
 
 						// Checked out successfully.
 						// Re-evaluate context.
@@ -420,17 +423,17 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					return m, nil
 
 				case "View Repos":
-					config, err := groveUtil.LoadConfig(m.path)
+					workspace, err := status.GetStatus(m.git, m.path)
 					if err != nil {
 						m.err = err
 						return m, nil
 					}
 					var repos []string
-					for name := range config.Repositories {
-						repos = append(repos, name)
+					for _, repo := range workspace.Repos {
+						repos = append(repos, repo.Name)
 					}
-					sort.Strings(repos)
 					m.repoChoices = repos
+					m.status = workspace
 					m.state = StateViewRepos
 					return m, nil
 
@@ -535,11 +538,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						Path: repoPath, // Should be relative path
 					}
 					// Only one repo
-					if err := registerrepo.RegisterRepo([]model.GGRepo{newRepo}, m.path); err != nil {
+					if _, err := registerrepo.RegisterRepo(m.git, []model.GGRepo{newRepo}, m.path); err != nil {
 						m.err = err
 					} else {
 						// Refresh context info
-						currentBranch, _ := gitUtil.CurrentBranch(m.path)
+						currentBranch, _ := m.git.CurrentBranch(m.path)
 						m.repoInfo = getTrunkContextInfo(m.path, currentBranch)
 						m.state = StateIdle
 					}
@@ -595,10 +598,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if len(m.repoChoices) > 0 {
 					repoName := m.repoChoices[m.repoCursor]
 					// Execute Prepare Merge
-					if err := preparemerge.PrepareMerge(m.path, repoName); err != nil {
+					if result, err := preparemerge.PrepareMerge(m.git, m.path, repoName); err != nil {
 						m.err = err
 					} else {
-						m.repoInfo = fmt.Sprintf("Success: Prepare-merge branch created for %s", repoName)
+						m.repoInfo = prepareMergeSummary(result)
 						m.state = StateIdle
 					}
 				}
@@ -643,17 +646,17 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case "enter":
 				if len(m.repoChoices) > 0 {
 					repoName := m.repoChoices[m.repoCursor]
-					currentBranch, err := gitUtil.CurrentBranch(m.path)
+					currentBranch, err := m.git.CurrentBranch(m.path)
 					if err != nil {
 						m.err = err
 						return m, nil
 					}
 					targetBranch := fmt.Sprintf("gg/%s/%s", currentBranch, repoName)
-					if err := gitUtil.Checkout(m.path, targetBranch); err != nil {
+					if err := m.git.Checkout(m.path, targetBranch); err != nil {
 						m.err = fmt.Errorf("failed to checkout %s: %v", targetBranch, err)
 					} else {
 						// Clean untracked files from previous context
-						if err := gitUtil.Clean(m.path); err != nil {
+						if err := m.git.Clean(m.path); err != nil {
 							// Warning state? For now just log err or ignore?
 							// Better to let user know?
 							// Let's treat it as non-fatal but info.
@@ -665,15 +668,15 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						}
 
 						// Set sticky context
-						if err := groveUtil.SetContextRepo(m.path, repoName); err != nil {
+						if err := groveUtil.SetContextRepo(m.git, m.path, repoName); err != nil {
 							m.err = fmt.Errorf("checkout success, but failed to set context: %v", err)
 						}
 						// Set sticky trunk
-						if err := groveUtil.SetContextTrunk(m.path, currentBranch); err != nil {
+						if err := groveUtil.SetContextTrunk(m.git, m.path, currentBranch); err != nil {
 							// Log error but proceed?
 						}
 						// Set sticky orphan branch (the one we just checked out)
-						if err := groveUtil.SetContextOrphan(m.path, targetBranch); err != nil {
+						if err := groveUtil.SetContextOrphan(m.git, m.path, targetBranch); err != nil {
 							m.err = fmt.Errorf("checkout success, but failed to set orphan context: %v", err)
 						}
 
@@ -695,7 +698,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case tea.KeyMsg:
 			switch msg.String() {
 			case "y", "Y":
-				if err := grovesync.ResetOrphanToTrunk(m.path, "", m.trunkBranch, m.orphanRepoName); err != nil {
+				if err := grovesync.ResetOrphanToTrunk(m.git, m.path, "", m.trunkBranch, m.orphanRepoName); err != nil {
 					m.err = err
 					m.repoInfo = "Error: Reset failed"
 				} else {
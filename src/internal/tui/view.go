@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/kuchuk-borom-debbarma/GitGrove/src/internal/grove/status"
 	groveUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/grove"
 )
 
@@ -18,12 +19,17 @@ func (m Model) View() string {
 	var s string
 
 	// Header
-	header := titleBorderStyle.Render(
-		lipgloss.JoinVertical(lipgloss.Center,
-			titleStyle.Render("GitGrove"),
-			infoStyle.Render("v1.1.2 ("+m.buildTime+")"),
-		),
-	)
+	headerLines := []string{
+		titleStyle.Render("GitGrove"),
+		infoStyle.Render("v1.1.2 (" + m.buildTime + ")"),
+	}
+	if m.scope != "" {
+		headerLines = append(headerLines, errorStyle.Render("Scope: "+m.scope+" (strict)"))
+	}
+	if m.hooksWarning != "" {
+		headerLines = append(headerLines, errorStyle.Render(m.hooksWarning))
+	}
+	header := titleBorderStyle.Render(lipgloss.JoinVertical(lipgloss.Center, headerLines...))
 
 	switch m.state {
 	case StateInit:
@@ -206,18 +212,27 @@ func (m Model) View() string {
 
 	case StateViewRepos:
 		s += "Registered Repositories:\n\n"
-		if len(m.repoChoices) == 0 {
+		if m.status == nil || len(m.status.Repos) == 0 {
 			s += errorStyle.Render("No repositories found.") + "\n"
 		} else {
-			for i, choice := range m.repoChoices {
+			for i, repo := range m.status.Repos {
 				cursor := " "
 				if m.repoCursor == i {
 					cursor = ">"
-					s += selectedItemStyle.Render(fmt.Sprintf("%s %s", cursor, choice)) + "\n"
+				}
+				line := fmt.Sprintf("%s %s", cursor, getRepoStatusLine(repo))
+				if m.repoCursor == i {
+					s += selectedItemStyle.Render(line) + "\n"
 				} else {
-					s += itemStyle.Render(fmt.Sprintf("%s %s", cursor, choice)) + "\n"
+					s += itemStyle.Render(line) + "\n"
 				}
 			}
+			if m.status.Dirty() {
+				s += "\n" + infoStyle.Render(fmt.Sprintf("Working tree has %d uncommitted change(s).", len(m.status.DirtyFiles))) + "\n"
+			}
+			for _, reason := range m.status.StaleContext {
+				s += errorStyle.Render("Stale context: "+reason) + "\n"
+			}
 		}
 		s += "\n" + infoStyle.Render("(esc/q/enter to return)") + "\n"
 
@@ -287,3 +302,52 @@ func getTrunkContextInfo(path string, currentBranch string) string {
 	}
 	return fmt.Sprintf("%s\n  Registered Repositories:\n    - %s", info, strings.Join(repos, "\n    - "))
 }
+
+// Helper to summarize a repository's status on one line (View Repos)
+func getRepoStatusLine(repo status.RepoStatus) string {
+	line := fmt.Sprintf("%s (%s)", repo.Name, repo.Path)
+	if repo.Current {
+		line += " [context]"
+	}
+	if !repo.OrphanExists {
+		line += " - orphan branch missing"
+	} else {
+		line += fmt.Sprintf(" - %d ahead, %d behind", repo.Ahead, repo.Behind)
+	}
+	if len(repo.MergePrep) > 0 {
+		line += fmt.Sprintf(", %d merge-prep pending", len(repo.MergePrep))
+	}
+	if repo.Checks != nil {
+		line += ", " + repo.Checks.Summary()
+	}
+	if repo.LastIntegration != nil {
+		line += ", integrated " + repo.LastIntegration.Date.Format("2006-01-02")
+	} else {
+		line += ", never integrated"
+	}
+	if repo.Dirty {
+		line += ", dirty"
+	}
+	if repo.Error != "" {
+		line += ", error: " + repo.Error
+	}
+	return line
+}
+
+// Helper to get formatted pre-integration check results from a report file (empty if none were recorded)
+func getCheckInfo(reportPath string) string {
+	report, err := groveUtil.ReadCheckReport(reportPath)
+	if err != nil || report == nil {
+		return ""
+	}
+
+	info := "\n  Pre-integration " + report.Summary()
+	for _, res := range report.Results {
+		status := "ok"
+		if !res.Passed {
+			status = fmt.Sprintf("FAILED (exit %d)", res.ExitCode)
+		}
+		info += fmt.Sprintf("\n    - %s: %s", res.Command, status)
+	}
+	return info
+}
@@ -0,0 +1,289 @@
+package gitUtil
+
+// GitClient is the set of git operations GitGrove uses. Grove packages receive a client instead of
+// running git themselves, so a faster backend or a fake can be injected.
+type GitClient interface {
+	// Queries (read-only; GitPath, GraftTree and CommitPatch only write to temporary files)
+	IsGitRepository(path string) error
+	RepoRoot() (string, error)
+	DiscoverWorkspace(path string) (*Workspace, error)
+	GetStagedFiles(repoPath string) ([]string, error)
+	CurrentBranch(repoPath string) (string, error)
+	FileExistsInBranch(repoPath string, branchName string, filePath string) (bool, error)
+	ReadFileFromBranch(repoPath string, branchName string, filePath string) ([]byte, error)
+	GetLocalConfig(repoPath string, key string) (string, error)
+	LogSubjects(repoPath string, base string, head string) ([]string, error)
+	GitDir(repoPath string) (string, error)
+	SubtreeSplitRev(repoPath string, prefix string, sourceRef string) (string, error)
+	RevList(repoPath string, args ...string) ([]string, error)
+	RevParse(repoPath string, rev string) (string, error)
+	TrailerValues(repoPath string, ref string, key string) ([]string, error)
+	GetCommitInfo(repoPath string, commit string) (*CommitInfo, error)
+	CommitPatch(repoPath string, commit string, pathspecs ...string) ([]byte, error)
+	GraftTree(repoPath string, base string, prefix string, subtree string, exclude ...string) (string, error)
+	Diff(repoPath string, args ...string) (string, error)
+	IsAncestor(repoPath string, ancestor string, descendant string) bool
+	Log(repoPath string, args ...string) (string, error)
+	CommitFiles(repoPath string, commit string) ([]string, error)
+	MergeBase(repoPath string, a string, b string) (string, error)
+	DiffNames(repoPath string, from string, to string) ([]string, error)
+	CommitParents(repoPath string, commit string) ([]string, error)
+	ListBranches(repoPath string, patterns ...string) ([]string, error)
+	GitPath(repoPath string, name string) (string, error)
+	StagedPaths(repoPath string) ([]string, error)
+	DirtyPaths(repoPath string) ([]string, error)
+	ObjectType(repoPath string, ref string, path string) (string, error)
+
+	// Commands (change refs, the index, the working tree or the config)
+	Commit(repoPath string, files []string, message string) error
+	CommitNoVerify(repoPath string, files []string, message string) error
+	SubtreeSplit(repoPath string, prefix string, branchName string) error
+	SubtreeMerge(repoPath string, prefix string, branchName string, base string, message string, exclude ...string) error
+	Checkout(repoPath string, branchName string) error
+	CreateBranch(repoPath string, branchName string) error
+	SetLocalConfig(repoPath string, key string, value string) error
+	UnsetLocalConfig(repoPath string, key string) error
+	SubtreeSplitFrom(repoPath string, prefix string, sourceRef string, branchName string) error
+	Merge(repoPath string, branchName string) error
+	DeleteBranch(repoPath string, branchName string, force bool) error
+	Clean(repoPath string) error
+	ResetHard(repoPath string, commit string) error
+	Push(repoPath string, remote string, branchName string) error
+	ApplyPatchToIndex(repoPath string, patch []byte, directory string) error
+	WriteTree(repoPath string) (string, error)
+	CommitTree(repoPath string, tree string, parents []string, message string, author *CommitInfo) (string, error)
+	UpdateRef(repoPath string, ref string, commit string) error
+	AddTrailer(repoPath string, msgFile string, key string, value string) error
+	AppendTrailers(repoPath string, message string, trailers ...string) (string, error)
+	ReadTree(repoPath string, tree string) error
+	ResetIndex(repoPath string, treeish string, paths ...string) error
+	ResetSoft(repoPath string, commit string) error
+	CommitStaged(repoPath string, message string, edit bool) error
+	RemovePaths(repoPath string, paths ...string) error
+}
+
+// ExecClient runs the git binary for every operation. It is the default client.
+type ExecClient struct{}
+
+// NewExecClient returns the exec based client.
+func NewExecClient() *ExecClient {
+	return &ExecClient{}
+}
+
+// The methods of ExecClient run the package functions of the same name.
+
+func (ExecClient) IsGitRepository(path string) error {
+	return IsGitRepository(path)
+}
+
+func (ExecClient) RepoRoot() (string, error) {
+	return RepoRoot()
+}
+
+func (ExecClient) DiscoverWorkspace(path string) (*Workspace, error) {
+	return DiscoverWorkspace(path)
+}
+
+func (ExecClient) Commit(repoPath string, files []string, message string) error {
+	return Commit(repoPath, files, message)
+}
+
+func (ExecClient) CommitNoVerify(repoPath string, files []string, message string) error {
+	return CommitNoVerify(repoPath, files, message)
+}
+
+func (ExecClient) SubtreeSplit(repoPath string, prefix string, branchName string) error {
+	return SubtreeSplit(repoPath, prefix, branchName)
+}
+
+func (ExecClient) GetStagedFiles(repoPath string) ([]string, error) {
+	return GetStagedFiles(repoPath)
+}
+
+func (ExecClient) SubtreeMerge(repoPath string, prefix string, branchName string, base string, message string, exclude ...string) error {
+	return SubtreeMerge(repoPath, prefix, branchName, base, message, exclude...)
+}
+
+func (ExecClient) CurrentBranch(repoPath string) (string, error) {
+	return CurrentBranch(repoPath)
+}
+
+func (ExecClient) Checkout(repoPath string, branchName string) error {
+	return Checkout(repoPath, branchName)
+}
+
+func (ExecClient) CreateBranch(repoPath string, branchName string) error {
+	return CreateBranch(repoPath, branchName)
+}
+
+func (ExecClient) FileExistsInBranch(repoPath string, branchName string, filePath string) (bool, error) {
+	return FileExistsInBranch(repoPath, branchName, filePath)
+}
+
+func (ExecClient) ReadFileFromBranch(repoPath string, branchName string, filePath string) ([]byte, error) {
+	return ReadFileFromBranch(repoPath, branchName, filePath)
+}
+
+func (ExecClient) SetLocalConfig(repoPath string, key string, value string) error {
+	return SetLocalConfig(repoPath, key, value)
+}
+
+func (ExecClient) GetLocalConfig(repoPath string, key string) (string, error) {
+	return GetLocalConfig(repoPath, key)
+}
+
+func (ExecClient) UnsetLocalConfig(repoPath string, key string) error {
+	return UnsetLocalConfig(repoPath, key)
+}
+
+func (ExecClient) SubtreeSplitFrom(repoPath string, prefix string, sourceRef string, branchName string) error {
+	return SubtreeSplitFrom(repoPath, prefix, sourceRef, branchName)
+}
+
+func (ExecClient) Merge(repoPath string, branchName string) error {
+	return Merge(repoPath, branchName)
+}
+
+func (ExecClient) DeleteBranch(repoPath string, branchName string, force bool) error {
+	return DeleteBranch(repoPath, branchName, force)
+}
+
+func (ExecClient) Clean(repoPath string) error {
+	return Clean(repoPath)
+}
+
+func (ExecClient) ResetHard(repoPath string, commit string) error {
+	return ResetHard(repoPath, commit)
+}
+
+func (ExecClient) Push(repoPath string, remote string, branchName string) error {
+	return Push(repoPath, remote, branchName)
+}
+
+func (ExecClient) LogSubjects(repoPath string, base string, head string) ([]string, error) {
+	return LogSubjects(repoPath, base, head)
+}
+
+func (ExecClient) GitDir(repoPath string) (string, error) {
+	return GitDir(repoPath)
+}
+
+func (ExecClient) SubtreeSplitRev(repoPath string, prefix string, sourceRef string) (string, error) {
+	return SubtreeSplitRev(repoPath, prefix, sourceRef)
+}
+
+func (ExecClient) RevList(repoPath string, args ...string) ([]string, error) {
+	return RevList(repoPath, args...)
+}
+
+func (ExecClient) RevParse(repoPath string, rev string) (string, error) {
+	return RevParse(repoPath, rev)
+}
+
+func (ExecClient) TrailerValues(repoPath string, ref string, key string) ([]string, error) {
+	return TrailerValues(repoPath, ref, key)
+}
+
+func (ExecClient) GetCommitInfo(repoPath string, commit string) (*CommitInfo, error) {
+	return GetCommitInfo(repoPath, commit)
+}
+
+func (ExecClient) CommitPatch(repoPath string, commit string, pathspecs ...string) ([]byte, error) {
+	return CommitPatch(repoPath, commit, pathspecs...)
+}
+
+func (ExecClient) ApplyPatchToIndex(repoPath string, patch []byte, directory string) error {
+	return ApplyPatchToIndex(repoPath, patch, directory)
+}
+
+func (ExecClient) WriteTree(repoPath string) (string, error) {
+	return WriteTree(repoPath)
+}
+
+func (ExecClient) CommitTree(repoPath string, tree string, parents []string, message string, author *CommitInfo) (string, error) {
+	return CommitTree(repoPath, tree, parents, message, author)
+}
+
+func (ExecClient) GraftTree(repoPath string, base string, prefix string, subtree string, exclude ...string) (string, error) {
+	return GraftTree(repoPath, base, prefix, subtree, exclude...)
+}
+
+func (ExecClient) Diff(repoPath string, args ...string) (string, error) {
+	return Diff(repoPath, args...)
+}
+
+func (ExecClient) UpdateRef(repoPath string, ref string, commit string) error {
+	return UpdateRef(repoPath, ref, commit)
+}
+
+func (ExecClient) IsAncestor(repoPath string, ancestor string, descendant string) bool {
+	return IsAncestor(repoPath, ancestor, descendant)
+}
+
+func (ExecClient) Log(repoPath string, args ...string) (string, error) {
+	return Log(repoPath, args...)
+}
+
+func (ExecClient) CommitFiles(repoPath string, commit string) ([]string, error) {
+	return CommitFiles(repoPath, commit)
+}
+
+func (ExecClient) MergeBase(repoPath string, a string, b string) (string, error) {
+	return MergeBase(repoPath, a, b)
+}
+
+func (ExecClient) DiffNames(repoPath string, from string, to string) ([]string, error) {
+	return DiffNames(repoPath, from, to)
+}
+
+func (ExecClient) CommitParents(repoPath string, commit string) ([]string, error) {
+	return CommitParents(repoPath, commit)
+}
+
+func (ExecClient) ListBranches(repoPath string, patterns ...string) ([]string, error) {
+	return ListBranches(repoPath, patterns...)
+}
+
+func (ExecClient) AddTrailer(repoPath string, msgFile string, key string, value string) error {
+	return AddTrailer(repoPath, msgFile, key, value)
+}
+
+func (ExecClient) AppendTrailers(repoPath string, message string, trailers ...string) (string, error) {
+	return AppendTrailers(repoPath, message, trailers...)
+}
+
+func (ExecClient) GitPath(repoPath string, name string) (string, error) {
+	return GitPath(repoPath, name)
+}
+
+func (ExecClient) StagedPaths(repoPath string) ([]string, error) {
+	return StagedPaths(repoPath)
+}
+
+func (ExecClient) DirtyPaths(repoPath string) ([]string, error) {
+	return DirtyPaths(repoPath)
+}
+
+func (ExecClient) ObjectType(repoPath string, ref string, path string) (string, error) {
+	return ObjectType(repoPath, ref, path)
+}
+
+func (ExecClient) ReadTree(repoPath string, tree string) error {
+	return ReadTree(repoPath, tree)
+}
+
+func (ExecClient) ResetIndex(repoPath string, treeish string, paths ...string) error {
+	return ResetIndex(repoPath, treeish, paths...)
+}
+
+func (ExecClient) ResetSoft(repoPath string, commit string) error {
+	return ResetSoft(repoPath, commit)
+}
+
+func (ExecClient) CommitStaged(repoPath string, message string, edit bool) error {
+	return CommitStaged(repoPath, message, edit)
+}
+
+func (ExecClient) RemovePaths(repoPath string, paths ...string) error {
+	return RemovePaths(repoPath, paths...)
+}
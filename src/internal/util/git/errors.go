@@ -0,0 +1,80 @@
+package gitUtil
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+)
+
+// Errors reported by git, matched with errors.Is. Failed git commands keep their usual message and
+// additionally wrap the sentinel their output was classified as.
+var (
+	ErrNotGitRepository = errors.New("not a git repository")
+	ErrBranchNotFound   = errors.New("branch or revision not found")
+	ErrDirtyWorktree    = errors.New("uncommitted changes in the working tree")
+	ErrMergeConflict    = errors.New("merge conflict")
+)
+
+// stderrPatterns maps lowercase git output fragments to the error they indicate.
+var stderrPatterns = []struct {
+	err       error
+	fragments []string
+}{
+	{ErrNotGitRepository, []string{"not a git repository"}},
+	{ErrMergeConflict, []string{
+		"conflict (",
+		"automatic merge failed",
+		"could not apply",
+		"you have not concluded your merge",
+		"you have unmerged paths",
+		"because you have unmerged files",
+	}},
+	{ErrDirtyWorktree, []string{
+		"your local changes to the following files would be overwritten",
+		"untracked working tree files would be",
+		"please commit your changes or stash them",
+		"you have unstaged changes",
+		"your index contains uncommitted changes",
+	}},
+	{ErrBranchNotFound, []string{
+		"did not match any file(s) known to git",
+		"not a valid object name",
+		"not a valid ref",
+		"invalid reference",
+		"unknown revision",
+		"bad revision",
+		"not something we can merge",
+		"couldn't find remote ref",
+	}},
+}
+
+// gitError is a failed git command classified by its output. The message is the command's error.
+type gitError struct {
+	kind error
+	err  error
+}
+
+func (e *gitError) Error() string   { return e.err.Error() }
+func (e *gitError) Unwrap() []error { return []error{e.kind, e.err} }
+
+// classify wraps err with the sentinel matching the command's output (combined output, or the
+// stderr captured by cmd.Output). err is returned as is when nothing matches.
+func classify(output []byte, err error) error {
+	if err == nil {
+		return nil
+	}
+	text := string(output)
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		text += string(exitErr.Stderr)
+	}
+	text = strings.ToLower(text)
+	for _, pattern := range stderrPatterns {
+		for _, fragment := range pattern.fragments {
+			if strings.Contains(text, fragment) {
+				return &gitError{kind: pattern.err, err: err}
+			}
+		}
+	}
+	return err
+}
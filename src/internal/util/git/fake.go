@@ -0,0 +1,272 @@
+package gitUtil
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FakeCommit is a commit of a FakeClient. Files is the full tree of the commit (path -> content).
+type FakeCommit struct {
+	Parents []string
+	Files   map[string]string
+	Message string
+}
+
+// FakeClient is an in-memory GitClient for unit tests of the grove packages. It answers the
+// queries about branches, commits, trees, config and the working tree from its maps; every other
+// operation goes to the embedded GitClient, which is nil (and panics) unless a test sets it.
+type FakeClient struct {
+	GitClient
+
+	Commits map[string]*FakeCommit // by SHA
+	Refs    map[string]string      // branch name -> SHA
+	Head    string                 // current branch
+	Config  map[string]string      // local git config
+	Staged  []string               // paths staged for commit
+	Dirty   []string               // paths with uncommitted changes (git status)
+}
+
+// NewFakeClient returns an empty FakeClient.
+func NewFakeClient() *FakeClient {
+	return &FakeClient{Commits: map[string]*FakeCommit{}, Refs: map[string]string{}, Config: map[string]string{}}
+}
+
+// AddCommit records a commit with the given parents and tree, moves branch to it (unless branch is
+// empty) and returns its SHA.
+func (f *FakeClient) AddCommit(branch string, message string, files map[string]string, parents ...string) string {
+	sha := fmt.Sprintf("%040x", len(f.Commits)+1)
+	f.Commits[sha] = &FakeCommit{Parents: parents, Files: files, Message: message}
+	if branch != "" {
+		f.Refs[branch] = sha
+	}
+	return sha
+}
+
+// resolve returns the SHA of a branch name (optionally refs/heads/ qualified), HEAD or a known SHA.
+func (f *FakeClient) resolve(rev string) (string, error) {
+	if rev == "HEAD" {
+		rev = f.Head
+	}
+	if sha, ok := f.Refs[strings.TrimPrefix(rev, "refs/heads/")]; ok {
+		return sha, nil
+	}
+	if _, ok := f.Commits[rev]; ok {
+		return rev, nil
+	}
+	return "", fmt.Errorf("cannot resolve revision '%s': %w", rev, ErrBranchNotFound)
+}
+
+func (f *FakeClient) commit(rev string) (*FakeCommit, error) {
+	sha, err := f.resolve(rev)
+	if err != nil {
+		return nil, err
+	}
+	return f.Commits[sha], nil
+}
+
+// ancestors returns sha and every commit reachable from it.
+func (f *FakeClient) ancestors(sha string) map[string]bool {
+	seen := map[string]bool{}
+	queue := []string{sha}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if seen[current] {
+			continue
+		}
+		seen[current] = true
+		if commit, ok := f.Commits[current]; ok {
+			queue = append(queue, commit.Parents...)
+		}
+	}
+	return seen
+}
+
+func (f *FakeClient) CurrentBranch(repoPath string) (string, error) {
+	return f.Head, nil
+}
+
+func (f *FakeClient) RevParse(repoPath string, rev string) (string, error) {
+	return f.resolve(rev)
+}
+
+func (f *FakeClient) ListBranches(repoPath string, patterns ...string) ([]string, error) {
+	branches := []string{}
+	for branch := range f.Refs {
+		if len(patterns) == 0 {
+			branches = append(branches, branch)
+			continue
+		}
+		for _, pattern := range patterns {
+			if strings.HasPrefix("refs/heads/"+branch, pattern) {
+				branches = append(branches, branch)
+				break
+			}
+		}
+	}
+	sort.Strings(branches)
+	return branches, nil
+}
+
+func (f *FakeClient) FileExistsInBranch(repoPath string, branchName string, filePath string) (bool, error) {
+	commit, err := f.commit(branchName)
+	if err != nil {
+		return false, err
+	}
+	_, exists := commit.Files[filePath]
+	return exists, nil
+}
+
+func (f *FakeClient) ReadFileFromBranch(repoPath string, branchName string, filePath string) ([]byte, error) {
+	commit, err := f.commit(branchName)
+	if err != nil {
+		return nil, err
+	}
+	content, exists := commit.Files[filePath]
+	if !exists {
+		return nil, fmt.Errorf("failed to read file '%s' from branch '%s': %w", filePath, branchName, ErrBranchNotFound)
+	}
+	return []byte(content), nil
+}
+
+func (f *FakeClient) ObjectType(repoPath string, ref string, path string) (string, error) {
+	commit, err := f.commit(ref)
+	if err != nil {
+		return "", err
+	}
+	if _, exists := commit.Files[path]; exists {
+		return "blob", nil
+	}
+	for file := range commit.Files {
+		if strings.HasPrefix(file, strings.TrimSuffix(path, "/")+"/") {
+			return "tree", nil
+		}
+	}
+	return "", fmt.Errorf("path '%s' not found in '%s'", path, ref)
+}
+
+func (f *FakeClient) CommitParents(repoPath string, commit string) ([]string, error) {
+	c, err := f.commit(commit)
+	if err != nil {
+		return nil, err
+	}
+	return append([]string{}, c.Parents...), nil
+}
+
+func (f *FakeClient) GetCommitInfo(repoPath string, commit string) (*CommitInfo, error) {
+	c, err := f.commit(commit)
+	if err != nil {
+		return nil, err
+	}
+	return &CommitInfo{Message: c.Message}, nil
+}
+
+func (f *FakeClient) DiffNames(repoPath string, from string, to string) ([]string, error) {
+	a, err := f.commit(from)
+	if err != nil {
+		return nil, err
+	}
+	b, err := f.commit(to)
+	if err != nil {
+		return nil, err
+	}
+	return diffTrees(a.Files, b.Files), nil
+}
+
+func (f *FakeClient) CommitFiles(repoPath string, commit string) ([]string, error) {
+	c, err := f.commit(commit)
+	if err != nil {
+		return nil, err
+	}
+	parent := map[string]string{}
+	if len(c.Parents) > 0 {
+		parent = f.Commits[c.Parents[0]].Files
+	}
+	return diffTrees(parent, c.Files), nil
+}
+
+func (f *FakeClient) MergeBase(repoPath string, a string, b string) (string, error) {
+	shaA, err := f.resolve(a)
+	if err != nil {
+		return "", err
+	}
+	shaB, err := f.resolve(b)
+	if err != nil {
+		return "", err
+	}
+	// Breadth-first from b: the first commit that a also reaches is the nearest common ancestor
+	ofA := f.ancestors(shaA)
+	seen := map[string]bool{}
+	queue := []string{shaB}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if seen[current] {
+			continue
+		}
+		seen[current] = true
+		if ofA[current] {
+			return current, nil
+		}
+		if commit, ok := f.Commits[current]; ok {
+			queue = append(queue, commit.Parents...)
+		}
+	}
+	return "", fmt.Errorf("no merge base between '%s' and '%s'", a, b)
+}
+
+func (f *FakeClient) IsAncestor(repoPath string, ancestor string, descendant string) bool {
+	shaA, err := f.resolve(ancestor)
+	if err != nil {
+		return false
+	}
+	shaD, err := f.resolve(descendant)
+	if err != nil {
+		return false
+	}
+	return f.ancestors(shaD)[shaA]
+}
+
+func (f *FakeClient) GetLocalConfig(repoPath string, key string) (string, error) {
+	return f.Config[key], nil
+}
+
+func (f *FakeClient) SetLocalConfig(repoPath string, key string, value string) error {
+	f.Config[key] = value
+	return nil
+}
+
+func (f *FakeClient) UnsetLocalConfig(repoPath string, key string) error {
+	delete(f.Config, key)
+	return nil
+}
+
+func (f *FakeClient) GetStagedFiles(repoPath string) ([]string, error) {
+	return append([]string{}, f.Staged...), nil
+}
+
+func (f *FakeClient) StagedPaths(repoPath string) ([]string, error) {
+	return append([]string{}, f.Staged...), nil
+}
+
+func (f *FakeClient) DirtyPaths(repoPath string) ([]string, error) {
+	return append([]string{}, f.Dirty...), nil
+}
+
+// diffTrees returns the paths added, removed or changed between two trees, sorted.
+func diffTrees(from map[string]string, to map[string]string) []string {
+	changed := []string{}
+	for path, content := range to {
+		if previous, ok := from[path]; !ok || previous != content {
+			changed = append(changed, path)
+		}
+	}
+	for path := range from {
+		if _, ok := to[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
@@ -1,6 +1,7 @@
 package gitUtil
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -8,19 +9,16 @@ import (
 	"strings"
 )
 
-// IsGitRepository checks if the given path is a valid git repository.
+// IsGitRepository checks that path is the root of a git working tree. The .git entry may be a
+// directory or a file (linked worktrees, submodules).
 func IsGitRepository(path string) error {
 	path = filepath.Clean(path)
-	gitDir := filepath.Join(path, ".git")
-	info, err := os.Stat(gitDir)
+	workspace, err := DiscoverWorkspace(path)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return fmt.Errorf("not a git repository: %s", path)
-		}
-		return fmt.Errorf("error checking git repository: %w", err)
+		return err
 	}
-	if !info.IsDir() {
-		return fmt.Errorf(".git is not a directory in %s", path)
+	if !samePath(path, workspace.Root) {
+		return fmt.Errorf("%s is not the root of the git repository %s", path, workspace.Root)
 	}
 	return nil
 }
@@ -30,11 +28,56 @@ func RepoRoot() (string, error) {
 	cmd := exec.Command("git", "rev-parse", "--show-toplevel")
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return "", fmt.Errorf("failed to get repo root: %s: %w", string(output), err)
+		return "", fmt.Errorf("failed to get repo root: %s: %w", string(output), classify(output, err))
 	}
 	return strings.TrimSpace(string(output)), nil
 }
 
+// Workspace locates the git repository containing a directory.
+type Workspace struct {
+	Root      string // top level of the working tree
+	CommonDir string // git dir shared by all worktrees (config, refs, hooks)
+}
+
+// DiscoverWorkspace resolves the working tree and common git dir containing path, which may be any
+// directory inside a repository, a linked worktree or a submodule.
+func DiscoverWorkspace(path string) (*Workspace, error) {
+	path = filepath.Clean(path)
+	cmd := exec.Command("git", "rev-parse", "--show-toplevel", "--git-common-dir")
+	cmd.Dir = path
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if info, statErr := os.Stat(path); statErr != nil || !info.IsDir() {
+			return nil, fmt.Errorf("error checking git repository: %s is not a directory", path)
+		}
+		if err = classify(output, err); errors.Is(err, ErrNotGitRepository) {
+			return nil, fmt.Errorf("%w: %s", ErrNotGitRepository, path)
+		}
+		return nil, fmt.Errorf("failed to discover git repository: %s: %w", string(output), err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	if len(lines) != 2 {
+		// A bare repository or the inside of a git dir has no working tree
+		return nil, fmt.Errorf("%w: %s has no working tree", ErrNotGitRepository, path)
+	}
+	commonDir := lines[1]
+	if !filepath.IsAbs(commonDir) {
+		commonDir = filepath.Join(path, commonDir)
+	}
+	return &Workspace{Root: filepath.Clean(lines[0]), CommonDir: filepath.Clean(commonDir)}, nil
+}
+
+// samePath reports whether two paths name the same directory, following symlinks.
+func samePath(a string, b string) bool {
+	if resolved, err := filepath.EvalSymlinks(a); err == nil {
+		a = resolved
+	}
+	if resolved, err := filepath.EvalSymlinks(b); err == nil {
+		b = resolved
+	}
+	return a == b
+}
+
 // Commit stages the given files and commits them with the provided message.
 func Commit(repoPath string, files []string, message string) error {
 	repoPath = filepath.Clean(repoPath)
@@ -43,14 +86,14 @@ func Commit(repoPath string, files []string, message string) error {
 	cmd := exec.Command("git", args...)
 	cmd.Dir = repoPath
 	if output, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("git add failed: %s: %w", string(output), err)
+		return fmt.Errorf("git add failed: %s: %w", string(output), classify(output, err))
 	}
 
 	// Commit
 	cmd = exec.Command("git", "commit", "-m", message)
 	cmd.Dir = repoPath
 	if output, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("git commit failed: %s: %w", string(output), err)
+		return fmt.Errorf("git commit failed: %s: %w", string(output), classify(output, err))
 	}
 
 	return nil
@@ -64,14 +107,14 @@ func CommitNoVerify(repoPath string, files []string, message string) error {
 	cmd := exec.Command("git", args...)
 	cmd.Dir = repoPath
 	if output, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("git add failed: %s: %w", string(output), err)
+		return fmt.Errorf("git add failed: %s: %w", string(output), classify(output, err))
 	}
 
 	// Commit with --no-verify
 	cmd = exec.Command("git", "commit", "--no-verify", "-m", message)
 	cmd.Dir = repoPath
 	if output, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("git commit --no-verify failed: %s: %w", string(output), err)
+		return fmt.Errorf("git commit --no-verify failed: %s: %w", string(output), classify(output, err))
 	}
 
 	return nil
@@ -84,7 +127,7 @@ func SubtreeSplit(repoPath string, prefix string, branchName string) error {
 	cmd := exec.Command("git", "subtree", "split", "--prefix="+prefix, "-b", branchName)
 	cmd.Dir = repoPath
 	if output, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("git subtree split failed: %s: %w", string(output), err)
+		return fmt.Errorf("git subtree split failed: %s: %w", string(output), classify(output, err))
 	}
 	return nil
 }
@@ -96,7 +139,7 @@ func GetStagedFiles(repoPath string) ([]string, error) {
 	cmd.Dir = repoPath
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return nil, fmt.Errorf("git diff failed: %s: %w", string(output), err)
+		return nil, fmt.Errorf("git diff failed: %s: %w", string(output), classify(output, err))
 	}
 
 	files := []string{}
@@ -109,16 +152,73 @@ func GetStagedFiles(repoPath string) ([]string, error) {
 	return files, nil
 }
 
-// SubtreeMerge merges the given branch using git subtree merge.
-func SubtreeMerge(repoPath string, prefix string, branchName string) error {
+// SubtreeMerge merges branchName into the directory prefix of the current branch. base is the commit
+// of branchName's history the trunk's copy of prefix last matched (empty if unknown), so only changes
+// made on either side since then are merged and real conflicts are reported instead of overwritten.
+// Paths in exclude (relative to prefix) are left out of the merge.
+//
+// A clean merge is committed with HEAD and branchName as parents. On conflicts the merge is left in
+// progress (conflict markers, MERGE_HEAD, MERGE_MSG) for `git commit` to conclude, and the error
+// wraps ErrMergeConflict. An empty message defaults to "Merge orphan branch <branchName>".
+func SubtreeMerge(repoPath string, prefix string, branchName string, base string, message string, exclude ...string) error {
 	repoPath = filepath.Clean(repoPath)
-	prefix = filepath.Clean(prefix)
-	// Use 'git merge -s subtree' directly to support --allow-unrelated-histories
-	// Use -Xtheirs to resolve "add/add" conflicts caused by unrelated histories (assuming main hasn't changed registered paths as per design)
-	cmd := exec.Command("git", "merge", "-s", "subtree", "--allow-unrelated-histories", "-Xsubtree="+prefix, "-Xtheirs", branchName, "-m", "Merge orphan branch "+branchName)
+	if message == "" {
+		message = "Merge orphan branch " + branchName
+	}
+	tip, err := RevParse(repoPath, branchName)
+	if err != nil {
+		return err
+	}
+	if base == "" {
+		base = EmptyTree
+	}
+	baseTree, err := GraftTree(repoPath, "HEAD", prefix, base, exclude...)
+	if err != nil {
+		return err
+	}
+	theirsTree, err := GraftTree(repoPath, "HEAD", prefix, tip, exclude...)
+	if err != nil {
+		return err
+	}
+
+	// The three trees only differ below prefix, so the merge cannot touch the rest of the trunk
+	cmd := exec.Command("git", "merge-recursive", baseTree, "--", "HEAD", theirsTree)
 	cmd.Dir = repoPath
 	if output, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("git merge -s subtree failed: %s: %w", string(output), err)
+		err = classify(output, err)
+		if errors.Is(err, ErrMergeConflict) {
+			if stateErr := writeMergeState(repoPath, tip, message); stateErr != nil {
+				return fmt.Errorf("%w (additionally, recording the merge failed: %v)", err, stateErr)
+			}
+		}
+		return fmt.Errorf("merge of %s into %s failed: %s: %w", branchName, prefix, string(output), err)
+	}
+
+	tree, err := WriteTree(repoPath)
+	if err != nil {
+		return err
+	}
+	head, err := RevParse(repoPath, "HEAD")
+	if err != nil {
+		return err
+	}
+	commit, err := CommitTree(repoPath, tree, []string{head, tip}, message, nil)
+	if err != nil {
+		return err
+	}
+	return UpdateRef(repoPath, "HEAD", commit)
+}
+
+// writeMergeState records an in-progress merge of commit, as `git merge` does when it stops on conflicts.
+func writeMergeState(repoPath string, commit string, message string) error {
+	for name, content := range map[string]string{"MERGE_HEAD": commit + "\n", "MERGE_MSG": message + "\n", "MERGE_MODE": "no-ff"} {
+		path, err := GitPath(repoPath, name)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
 	}
 	return nil
 }
@@ -130,7 +230,7 @@ func CurrentBranch(repoPath string) (string, error) {
 	cmd.Dir = repoPath
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return "", fmt.Errorf("git current-branch failed: %s: %w", string(output), err)
+		return "", fmt.Errorf("git current-branch failed: %s: %w", string(output), classify(output, err))
 	}
 	return strings.TrimSpace(string(output)), nil
 }
@@ -141,7 +241,7 @@ func Checkout(repoPath string, branchName string) error {
 	cmd := exec.Command("git", "checkout", branchName)
 	cmd.Dir = repoPath
 	if output, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("git checkout failed: %s: %w", string(output), err)
+		return fmt.Errorf("git checkout failed: %s: %w", string(output), classify(output, err))
 	}
 	return nil
 }
@@ -152,7 +252,7 @@ func CreateBranch(repoPath string, branchName string) error {
 	cmd := exec.Command("git", "checkout", "-b", branchName)
 	cmd.Dir = repoPath
 	if output, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("git create-branch failed: %s: %w", string(output), err)
+		return fmt.Errorf("git create-branch failed: %s: %w", string(output), classify(output, err))
 	}
 	return nil
 }
@@ -189,7 +289,7 @@ func ReadFileFromBranch(repoPath string, branchName string, filePath string) ([]
 	cmd.Dir = repoPath
 	output, err := cmd.Output()
 	if err != nil {
-		return nil, fmt.Errorf("failed to read file '%s' from branch '%s': %w", filePath, branchName, err)
+		return nil, fmt.Errorf("failed to read file '%s' from branch '%s': %w", filePath, branchName, classify(nil, err))
 	}
 	return output, nil
 }
@@ -200,7 +300,7 @@ func SetLocalConfig(repoPath string, key string, value string) error {
 	cmd := exec.Command("git", "config", "--local", key, value)
 	cmd.Dir = repoPath
 	if output, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("failed to set config %s=%s: %s: %w", key, value, string(output), err)
+		return fmt.Errorf("failed to set config %s=%s: %s: %w", key, value, string(output), classify(output, err))
 	}
 	return nil
 }
@@ -238,7 +338,7 @@ func SubtreeSplitFrom(repoPath string, prefix string, sourceRef string, branchNa
 	cmd := exec.Command("git", "subtree", "split", "--prefix="+prefix, "-b", branchName, sourceRef)
 	cmd.Dir = repoPath
 	if output, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("git subtree split from %s failed: %s: %w", sourceRef, string(output), err)
+		return fmt.Errorf("git subtree split from %s failed: %s: %w", sourceRef, string(output), classify(output, err))
 	}
 	return nil
 }
@@ -250,7 +350,7 @@ func Merge(repoPath string, branchName string) error {
 	cmd := exec.Command("git", "merge", "--allow-unrelated-histories", branchName)
 	cmd.Dir = repoPath
 	if output, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("git merge failed: %s: %w", string(output), err)
+		return fmt.Errorf("git merge failed: %s: %w", string(output), classify(output, err))
 	}
 	return nil
 }
@@ -265,7 +365,7 @@ func DeleteBranch(repoPath string, branchName string, force bool) error {
 	cmd := exec.Command("git", "branch", flag, branchName)
 	cmd.Dir = repoPath
 	if output, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("git branch %s %s failed: %s: %w", flag, branchName, string(output), err)
+		return fmt.Errorf("git branch %s %s failed: %s: %w", flag, branchName, string(output), classify(output, err))
 	}
 	return nil
 }
@@ -279,7 +379,7 @@ func Clean(repoPath string) error {
 	cmd := exec.Command("git", "clean", "-fdx")
 	cmd.Dir = repoPath
 	if output, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("git clean -fdx failed: %s: %w", string(output), err)
+		return fmt.Errorf("git clean -fdx failed: %s: %w", string(output), classify(output, err))
 	}
 	return nil
 }
@@ -291,7 +391,573 @@ func ResetHard(repoPath string, commit string) error {
 	cmd := exec.Command("git", "reset", "--hard", commit)
 	cmd.Dir = repoPath
 	if output, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("git reset --hard %s failed: %s: %w", commit, string(output), err)
+		return fmt.Errorf("git reset --hard %s failed: %s: %w", commit, string(output), classify(output, err))
+	}
+	return nil
+}
+
+// Push pushes the given branch to the remote and sets it as the upstream.
+func Push(repoPath string, remote string, branchName string) error {
+	repoPath = filepath.Clean(repoPath)
+	cmd := exec.Command("git", "push", "--set-upstream", remote, branchName)
+	cmd.Dir = repoPath
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git push %s %s failed: %s: %w", remote, branchName, string(output), classify(output, err))
+	}
+	return nil
+}
+
+// LogSubjects returns "<short-sha> <subject>" lines for commits reachable from head but not from base.
+func LogSubjects(repoPath string, base string, head string) ([]string, error) {
+	repoPath = filepath.Clean(repoPath)
+	cmd := exec.Command("git", "log", "--no-merges", "--format=%h %s", base+".."+head)
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git log %s..%s failed: %w", base, head, classify(nil, err))
+	}
+
+	lines := []string{}
+	for _, line := range strings.Split(string(output), "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, strings.TrimSpace(line))
+		}
+	}
+	return lines, nil
+}
+
+// GitDir returns the absolute path to the repository's .git directory.
+func GitDir(repoPath string) (string, error) {
+	repoPath = filepath.Clean(repoPath)
+	cmd := exec.Command("git", "rev-parse", "--absolute-git-dir")
+	cmd.Dir = repoPath
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve git dir: %s: %w", string(output), classify(output, err))
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// SubtreeSplitRev runs git subtree split for a ref and returns the resulting commit SHA without creating a branch.
+func SubtreeSplitRev(repoPath string, prefix string, sourceRef string) (string, error) {
+	repoPath = filepath.Clean(repoPath)
+	prefix = filepath.ToSlash(filepath.Clean(prefix))
+	gitDir, err := GitDir(repoPath)
+	if err != nil {
+		return "", err
+	}
+	// git subtree refuses to run unless prefix exists in the working tree, even when splitting another
+	// ref (e.g. while an orphan branch is checked out). Run it in a stand-in work tree that has prefix.
+	workTree, err := os.MkdirTemp("", "gg-split-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary work tree: %w", err)
+	}
+	defer os.RemoveAll(workTree)
+	if err := os.MkdirAll(filepath.Join(workTree, filepath.FromSlash(prefix)), 0755); err != nil {
+		return "", fmt.Errorf("failed to create temporary work tree: %w", err)
+	}
+
+	cmd := exec.Command("git", "subtree", "split", "--prefix="+prefix, sourceRef)
+	cmd.Dir = workTree
+	cmd.Env = append(os.Environ(), "GIT_DIR="+gitDir, "GIT_WORK_TREE="+workTree)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git subtree split of %s at %s failed: %w", prefix, sourceRef, classify(nil, err))
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// RevList runs git rev-list with the given arguments and returns the listed commit SHAs.
+func RevList(repoPath string, args ...string) ([]string, error) {
+	repoPath = filepath.Clean(repoPath)
+	cmd := exec.Command("git", append([]string{"rev-list"}, args...)...)
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git rev-list %s failed: %w", strings.Join(args, " "), classify(nil, err))
+	}
+	return strings.Fields(string(output)), nil
+}
+
+// RevParse resolves a revision to its full SHA.
+func RevParse(repoPath string, rev string) (string, error) {
+	repoPath = filepath.Clean(repoPath)
+	cmd := exec.Command("git", "rev-parse", "--verify", "--quiet", rev)
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		// --quiet leaves nothing to classify when the revision simply does not exist
+		classified := classify(nil, err)
+		if classified == err {
+			classified = &gitError{kind: ErrBranchNotFound, err: err}
+		}
+		return "", fmt.Errorf("cannot resolve revision '%s': %w", rev, classified)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// TrailerValues returns the values of the given trailer key found in commits reachable from ref.
+func TrailerValues(repoPath string, ref string, key string) ([]string, error) {
+	repoPath = filepath.Clean(repoPath)
+	format := fmt.Sprintf("--format=%%(trailers:key=%s,valueonly)", key)
+	cmd := exec.Command("git", "log", format, ref)
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git log %s failed: %w", ref, classify(nil, err))
+	}
+
+	values := []string{}
+	for _, line := range strings.Split(string(output), "\n") {
+		if strings.TrimSpace(line) != "" {
+			values = append(values, strings.TrimSpace(line))
+		}
+	}
+	return values, nil
+}
+
+// CommitInfo holds the metadata of a commit needed to recreate it.
+type CommitInfo struct {
+	SHA         string
+	AuthorName  string
+	AuthorEmail string
+	AuthorDate  string // ISO 8601
+	Message     string
+}
+
+// GetCommitInfo reads the author and message of a commit.
+func GetCommitInfo(repoPath string, commit string) (*CommitInfo, error) {
+	repoPath = filepath.Clean(repoPath)
+	cmd := exec.Command("git", "show", "-s", "--format=%H%x00%an%x00%ae%x00%aI%x00%B", commit)
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read commit %s: %w", commit, classify(nil, err))
+	}
+
+	fields := strings.SplitN(string(output), "\x00", 5)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("unexpected git show output for commit %s", commit)
+	}
+	return &CommitInfo{
+		SHA:         fields[0],
+		AuthorName:  fields[1],
+		AuthorEmail: fields[2],
+		AuthorDate:  fields[3],
+		Message:     strings.TrimRight(fields[4], "\n"),
+	}, nil
+}
+
+// CommitPatch returns the binary-safe patch a commit introduces relative to its first parent
+// (or the empty tree for root commits). Pathspecs can be used to limit or exclude paths.
+func CommitPatch(repoPath string, commit string, pathspecs ...string) ([]byte, error) {
+	repoPath = filepath.Clean(repoPath)
+	args := []string{"diff-tree", "-p", "--binary", "--full-index", "--root", "--no-commit-id", commit}
+	if len(pathspecs) > 0 {
+		args = append(append(args, "--"), pathspecs...)
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git diff-tree %s failed: %w", commit, classify(nil, err))
+	}
+	return output, nil
+}
+
+// ApplyPatchToIndex applies a patch to the index only, prefixing every path with directory.
+func ApplyPatchToIndex(repoPath string, patch []byte, directory string) error {
+	repoPath = filepath.Clean(repoPath)
+	args := []string{"apply", "--cached"}
+	if directory != "" && directory != "." {
+		args = append(args, "--directory="+filepath.ToSlash(filepath.Clean(directory)))
+	}
+	cmd := exec.Command("git", append(args, "-")...)
+	cmd.Dir = repoPath
+	cmd.Stdin = strings.NewReader(string(patch))
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git apply failed: %s: %w", string(output), classify(output, err))
+	}
+	return nil
+}
+
+// WriteTree writes the current index as a tree object and returns its SHA.
+func WriteTree(repoPath string) (string, error) {
+	repoPath = filepath.Clean(repoPath)
+	cmd := exec.Command("git", "write-tree")
+	cmd.Dir = repoPath
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("git write-tree failed: %s: %w", string(output), classify(output, err))
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// CommitTree creates a commit object for tree with the given parents, preserving the author from info.
+// No hooks run. Returns the new commit SHA.
+func CommitTree(repoPath string, tree string, parents []string, message string, author *CommitInfo) (string, error) {
+	repoPath = filepath.Clean(repoPath)
+	args := []string{"commit-tree", tree}
+	for _, parent := range parents {
+		args = append(args, "-p", parent)
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoPath
+	cmd.Stdin = strings.NewReader(message)
+	cmd.Env = os.Environ()
+	if author != nil {
+		cmd.Env = append(cmd.Env,
+			"GIT_AUTHOR_NAME="+author.AuthorName,
+			"GIT_AUTHOR_EMAIL="+author.AuthorEmail,
+			"GIT_AUTHOR_DATE="+author.AuthorDate,
+		)
+	}
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git commit-tree failed: %w", classify(nil, err))
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// EmptyTree is the SHA of git's empty tree object.
+const EmptyTree = "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+
+// GraftTree returns the tree of base with the directory prefix replaced by the tree of subtree
+// (a commit or tree), without touching the index or working tree. Paths in exclude (relative to
+// subtree) are left out.
+func GraftTree(repoPath string, base string, prefix string, subtree string, exclude ...string) (string, error) {
+	repoPath = filepath.Clean(repoPath)
+	prefix = strings.TrimSuffix(filepath.ToSlash(filepath.Clean(prefix)), "/")
+
+	indexFile, err := os.CreateTemp("", "gg-index-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary index: %w", err)
+	}
+	indexFile.Close()
+	defer os.Remove(indexFile.Name())
+
+	steps := [][]string{
+		{"read-tree", base},
+		{"rm", "-r", "-q", "--cached", "--ignore-unmatch", "--", prefix},
+		{"read-tree", "--prefix=" + prefix + "/", subtree},
+	}
+	for _, path := range exclude {
+		steps = append(steps, []string{"rm", "-r", "-q", "--cached", "--ignore-unmatch", "--", prefix + "/" + path})
+	}
+	for _, args := range steps {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoPath
+		cmd.Env = append(os.Environ(), "GIT_INDEX_FILE="+indexFile.Name())
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return "", fmt.Errorf("git %s failed: %s: %w", args[0], string(output), classify(output, err))
+		}
+	}
+
+	cmd := exec.Command("git", "write-tree")
+	cmd.Dir = repoPath
+	cmd.Env = append(os.Environ(), "GIT_INDEX_FILE="+indexFile.Name())
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git write-tree failed: %w", classify(nil, err))
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// Diff runs git diff with the given arguments and returns its raw output (without color).
+func Diff(repoPath string, args ...string) (string, error) {
+	repoPath = filepath.Clean(repoPath)
+	cmd := exec.Command("git", append([]string{"diff", "--no-color"}, args...)...)
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git diff %s failed: %w", strings.Join(args, " "), classify(nil, err))
+	}
+	return string(output), nil
+}
+
+// UpdateRef points ref at the given commit.
+func UpdateRef(repoPath string, ref string, commit string) error {
+	repoPath = filepath.Clean(repoPath)
+	cmd := exec.Command("git", "update-ref", ref, commit)
+	cmd.Dir = repoPath
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git update-ref %s failed: %s: %w", ref, string(output), classify(output, err))
+	}
+	return nil
+}
+
+// IsAncestor reports whether ancestor is reachable from descendant.
+func IsAncestor(repoPath string, ancestor string, descendant string) bool {
+	repoPath = filepath.Clean(repoPath)
+	cmd := exec.Command("git", "merge-base", "--is-ancestor", ancestor, descendant)
+	cmd.Dir = repoPath
+	return cmd.Run() == nil
+}
+
+// Log runs git log with the given arguments and returns its raw output.
+func Log(repoPath string, args ...string) (string, error) {
+	repoPath = filepath.Clean(repoPath)
+	cmd := exec.Command("git", append([]string{"log"}, args...)...)
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git log %s failed: %w", strings.Join(args, " "), classify(nil, err))
+	}
+	return string(output), nil
+}
+
+// CommitFiles returns the paths a commit changes relative to its first parent (all paths for root commits).
+func CommitFiles(repoPath string, commit string) ([]string, error) {
+	repoPath = filepath.Clean(repoPath)
+	cmd := exec.Command("git", "diff-tree", "--no-commit-id", "--name-only", "-r", "--root", commit)
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git diff-tree %s failed: %w", commit, classify(nil, err))
+	}
+
+	files := []string{}
+	for _, line := range strings.Split(string(output), "\n") {
+		if strings.TrimSpace(line) != "" {
+			files = append(files, strings.TrimSpace(line))
+		}
+	}
+	return files, nil
+}
+
+// MergeBase returns the best common ancestor of two revisions.
+func MergeBase(repoPath string, a string, b string) (string, error) {
+	repoPath = filepath.Clean(repoPath)
+	cmd := exec.Command("git", "merge-base", a, b)
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git merge-base %s %s failed: %w", a, b, classify(nil, err))
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// DiffNames returns the paths that differ between two revisions (git diff --name-only from to).
+// Renames are reported as a deletion plus an addition.
+func DiffNames(repoPath string, from string, to string) ([]string, error) {
+	repoPath = filepath.Clean(repoPath)
+	cmd := exec.Command("git", "diff", "--name-only", "--no-renames", "-z", from, to, "--")
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git diff %s %s failed: %w", from, to, classify(nil, err))
+	}
+
+	files := []string{}
+	for _, path := range strings.Split(string(output), "\x00") {
+		if path != "" {
+			files = append(files, path)
+		}
+	}
+	return files, nil
+}
+
+// CommitParents returns the parent SHAs of a commit.
+func CommitParents(repoPath string, commit string) ([]string, error) {
+	repoPath = filepath.Clean(repoPath)
+	cmd := exec.Command("git", "rev-list", "--parents", "-n", "1", commit)
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git rev-list --parents %s failed: %w", commit, classify(nil, err))
+	}
+	fields := strings.Fields(string(output))
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("unknown commit %s", commit)
+	}
+	return fields[1:], nil
+}
+
+// ListBranches returns the short names of local branches matching the given for-each-ref patterns
+// (e.g. "refs/heads/gg/"). Without patterns all local branches are listed.
+func ListBranches(repoPath string, patterns ...string) ([]string, error) {
+	repoPath = filepath.Clean(repoPath)
+	if len(patterns) == 0 {
+		patterns = []string{"refs/heads/"}
+	}
+	cmd := exec.Command("git", append([]string{"for-each-ref", "--format=%(refname:short)"}, patterns...)...)
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git for-each-ref failed: %w", classify(nil, err))
+	}
+	return strings.Fields(string(output)), nil
+}
+
+// AddTrailer appends a "key: value" trailer to the commit message in msgFile (git interpret-trailers).
+func AddTrailer(repoPath string, msgFile string, key string, value string) error {
+	repoPath = filepath.Clean(repoPath)
+	cmd := exec.Command("git", "interpret-trailers", "--in-place", "--if-exists", "doNothing", "--trailer", fmt.Sprintf("%s: %s", key, value), msgFile)
+	cmd.Dir = repoPath
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git interpret-trailers failed: %s: %w", string(output), classify(output, err))
+	}
+	return nil
+}
+
+// AppendTrailers returns message with the given "key: value" trailers added to its trailer block
+// (git interpret-trailers), so trailers already in the message stay trailers.
+func AppendTrailers(repoPath string, message string, trailers ...string) (string, error) {
+	repoPath = filepath.Clean(repoPath)
+	args := []string{"interpret-trailers"}
+	for _, trailer := range trailers {
+		args = append(args, "--trailer", trailer)
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoPath
+	// Without a final newline the last line would be read as the start of the trailer block
+	cmd.Stdin = strings.NewReader(strings.TrimRight(message, "\n") + "\n")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git interpret-trailers failed: %w", classify(nil, err))
+	}
+	return string(output), nil
+}
+
+// GitPath resolves a path inside the git directory (git rev-parse --git-path), honoring
+// core.hooksPath for "hooks" and the common directory of linked worktrees.
+func GitPath(repoPath string, name string) (string, error) {
+	repoPath = filepath.Clean(repoPath)
+	cmd := exec.Command("git", "rev-parse", "--git-path", name)
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse --git-path %s failed: %w", name, classify(nil, err))
+	}
+	path := strings.TrimSpace(string(output))
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(repoPath, path)
+	}
+	return filepath.Clean(path), nil
+}
+
+// StagedPaths returns every path changed in the index relative to HEAD. Unlike GetStagedFiles,
+// renames are reported as a deletion plus an addition so both sides are listed.
+func StagedPaths(repoPath string) ([]string, error) {
+	repoPath = filepath.Clean(repoPath)
+	cmd := exec.Command("git", "diff", "--cached", "--name-only", "--no-renames", "-z")
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git diff --cached failed: %w", classify(nil, err))
+	}
+
+	files := []string{}
+	for _, path := range strings.Split(string(output), "\x00") {
+		if path != "" {
+			files = append(files, path)
+		}
+	}
+	return files, nil
+}
+
+// DirtyPaths returns the paths with uncommitted changes: staged, unstaged and untracked (git status).
+// Renames are reported as a deletion plus an addition.
+func DirtyPaths(repoPath string) ([]string, error) {
+	repoPath = filepath.Clean(repoPath)
+	cmd := exec.Command("git", "status", "--porcelain", "-z", "--no-renames", "--untracked-files=all")
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git status failed: %w", classify(nil, err))
+	}
+
+	files := []string{}
+	for _, entry := range strings.Split(string(output), "\x00") {
+		// "XY path"
+		if len(entry) > 3 {
+			files = append(files, entry[3:])
+		}
+	}
+	return files, nil
+}
+
+// ReadTree replaces the whole index with the given tree.
+func ReadTree(repoPath string, tree string) error {
+	repoPath = filepath.Clean(repoPath)
+	cmd := exec.Command("git", "read-tree", tree)
+	cmd.Dir = repoPath
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git read-tree failed: %s: %w", string(output), classify(output, err))
+	}
+	return nil
+}
+
+// ResetIndex sets the index entries for paths to their state in treeish, leaving the working tree
+// alone. Paths are matched literally. Without paths the whole index is reset.
+func ResetIndex(repoPath string, treeish string, paths ...string) error {
+	repoPath = filepath.Clean(repoPath)
+	args := []string{"--literal-pathspecs", "reset", "-q", treeish}
+	if len(paths) > 0 {
+		args = append(append(args, "--"), paths...)
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoPath
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git reset failed: %s: %w", string(output), classify(output, err))
+	}
+	return nil
+}
+
+// ResetSoft moves the current branch to commit, keeping the index and working tree.
+func ResetSoft(repoPath string, commit string) error {
+	repoPath = filepath.Clean(repoPath)
+	cmd := exec.Command("git", "reset", "-q", "--soft", commit)
+	cmd.Dir = repoPath
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git reset --soft failed: %s: %w", string(output), classify(output, err))
+	}
+	return nil
+}
+
+// CommitStaged commits the current index with message, running the hooks. With edit, the editor is
+// opened on the terminal (pre-filled with message) and the hook output goes to the terminal.
+func CommitStaged(repoPath string, message string, edit bool) error {
+	repoPath = filepath.Clean(repoPath)
+	args := []string{"commit", "-m", message}
+	if edit {
+		args = append(args, "--edit")
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoPath
+	if edit {
+		cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("git commit failed: %w", err)
+		}
+		return nil
+	}
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git commit failed: %s: %w", string(output), classify(output, err))
+	}
+	return nil
+}
+
+// ObjectType returns the type of path in ref ("tree" for a directory, "blob" for a file).
+func ObjectType(repoPath string, ref string, path string) (string, error) {
+	repoPath = filepath.Clean(repoPath)
+	object := fmt.Sprintf("%s:%s", ref, filepath.ToSlash(path))
+	cmd := exec.Command("git", "cat-file", "-t", object)
+	cmd.Dir = repoPath
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("path '%s' not found in '%s': %s: %w", path, ref, strings.TrimSpace(string(output)), classify(output, err))
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// RemovePaths removes tracked paths from the index and the working tree (git rm).
+func RemovePaths(repoPath string, paths ...string) error {
+	repoPath = filepath.Clean(repoPath)
+	cmd := exec.Command("git", append([]string{"rm", "-q", "--"}, paths...)...)
+	cmd.Dir = repoPath
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git rm failed: %s: %w", string(output), classify(output, err))
 	}
 	return nil
 }
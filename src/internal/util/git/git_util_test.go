@@ -1,6 +1,8 @@
 package gitUtil
 
 import (
+	"bytes"
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -50,3 +52,176 @@ func TestClean(t *testing.T) {
 	// If the user wants "dirs and all" gone, they imply ignored files too.
 	assert.NoFileExists(t, ignoredFile)
 }
+
+func TestErrorClassification(t *testing.T) {
+	dir := t.TempDir()
+	exec.Command("git", "init", "--initial-branch=main", dir).Run()
+	exec.Command("git", "-C", dir, "config", "user.email", "test@example.com").Run()
+	exec.Command("git", "-C", dir, "config", "user.name", "Test User").Run()
+	os.WriteFile(filepath.Join(dir, "file.txt"), []byte("base\n"), 0644)
+	assert.NoError(t, CommitNoVerify(dir, []string{"."}, "base"))
+
+	err := Checkout(dir, "missing")
+	assert.True(t, errors.Is(err, ErrBranchNotFound), "checkout of a missing branch: %v", err)
+	_, err = RevParse(dir, "missing")
+	assert.True(t, errors.Is(err, ErrBranchNotFound), "rev-parse of a missing revision: %v", err)
+	_, err = CurrentBranch(t.TempDir())
+	assert.True(t, errors.Is(err, ErrNotGitRepository), "outside a repository: %v", err)
+
+	// Diverging edits of the same line
+	exec.Command("git", "-C", dir, "checkout", "-q", "-b", "other").Run()
+	os.WriteFile(filepath.Join(dir, "file.txt"), []byte("other\n"), 0644)
+	assert.NoError(t, CommitNoVerify(dir, []string{"."}, "other"))
+	assert.NoError(t, Checkout(dir, "main"))
+	os.WriteFile(filepath.Join(dir, "file.txt"), []byte("main\n"), 0644)
+
+	err = Checkout(dir, "other")
+	assert.True(t, errors.Is(err, ErrDirtyWorktree), "checkout over local changes: %v", err)
+
+	assert.NoError(t, CommitNoVerify(dir, []string{"."}, "main"))
+	err = Merge(dir, "other")
+	assert.True(t, errors.Is(err, ErrMergeConflict), "conflicting merge: %v", err)
+	assert.Contains(t, err.Error(), "git merge failed")
+}
+
+func TestGoGitClientMatchesExec(t *testing.T) {
+	dir := t.TempDir()
+	exec.Command("git", "init", "--initial-branch=main", dir).Run()
+	exec.Command("git", "-C", dir, "config", "user.email", "test@example.com").Run()
+	exec.Command("git", "-C", dir, "config", "user.name", "Test User").Run()
+	execClient, goGit := NewExecClient(), NewGoGitClient(nil)
+
+	// Unborn branch
+	branch, err := goGit.CurrentBranch(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "main", branch)
+
+	os.MkdirAll(filepath.Join(dir, "services", "api"), 0755)
+	os.WriteFile(filepath.Join(dir, "services", "api", "file.txt"), []byte("content\n"), 0644)
+	assert.NoError(t, CommitNoVerify(dir, []string{"."}, "base"))
+	exec.Command("git", "-C", dir, "tag", "v1").Run()
+	exec.Command("git", "-C", dir, "branch", "gg/main/api").Run()
+	assert.NoError(t, SetLocalConfig(dir, "gitgrove.context.repo", "api"))
+
+	for _, client := range []GitClient{execClient, goGit} {
+		branch, err := client.CurrentBranch(dir)
+		assert.NoError(t, err)
+		assert.Equal(t, "main", branch)
+
+		head, _ := execClient.RevParse(dir, "HEAD")
+		for _, rev := range []string{"main", "gg/main/api", "v1", "refs/heads/main", "HEAD"} {
+			hash, err := client.RevParse(dir, rev)
+			assert.NoError(t, err)
+			assert.Equal(t, head, hash, "rev-parse %s", rev)
+		}
+		_, err = client.RevParse(dir, "missing")
+		assert.True(t, errors.Is(err, ErrBranchNotFound), "missing branch: %v", err)
+
+		data, err := client.ReadFileFromBranch(dir, "gg/main/api", "services/api/file.txt")
+		assert.NoError(t, err)
+		assert.Equal(t, "content\n", string(data))
+		_, err = client.ReadFileFromBranch(dir, "main", "missing.txt")
+		assert.Error(t, err)
+
+		for path, want := range map[string]bool{"services/api/file.txt": true, "services/api": true, "missing.txt": false} {
+			exists, err := client.FileExistsInBranch(dir, "main", path)
+			assert.NoError(t, err)
+			assert.Equal(t, want, exists, "exists %s", path)
+		}
+
+		value, err := client.GetLocalConfig(dir, "gitgrove.context.repo")
+		assert.NoError(t, err)
+		assert.Equal(t, "api", value)
+		value, err = client.GetLocalConfig(dir, "gitgrove.context.trunk")
+		assert.NoError(t, err)
+		assert.Equal(t, "", value)
+
+		// From a subdirectory
+		branch, err = client.CurrentBranch(filepath.Join(dir, "services"))
+		assert.NoError(t, err)
+		assert.Equal(t, "main", branch)
+	}
+
+	// Detached HEAD falls back to git
+	exec.Command("git", "-C", dir, "checkout", "-q", "--detach").Run()
+	want, _ := execClient.CurrentBranch(dir)
+	got, _ := goGit.CurrentBranch(dir)
+	assert.Equal(t, want, got)
+}
+
+func TestDiscoverWorkspace(t *testing.T) {
+	dir, _ := filepath.EvalSymlinks(t.TempDir())
+	repo := filepath.Join(dir, "repo")
+	exec.Command("git", "init", "--initial-branch=main", repo).Run()
+	exec.Command("git", "-C", repo, "config", "user.email", "test@example.com").Run()
+	exec.Command("git", "-C", repo, "config", "user.name", "Test User").Run()
+	os.MkdirAll(filepath.Join(repo, "services", "a"), 0755)
+	os.WriteFile(filepath.Join(repo, "services", "a", "file.txt"), []byte("a\n"), 0644)
+	assert.NoError(t, CommitNoVerify(repo, []string{"."}, "base"))
+	commonDir := filepath.Join(repo, ".git")
+
+	// Subdirectory
+	workspace, err := DiscoverWorkspace(filepath.Join(repo, "services", "a"))
+	assert.NoError(t, err)
+	assert.Equal(t, &Workspace{Root: repo, CommonDir: commonDir}, workspace)
+	assert.NoError(t, IsGitRepository(repo))
+	assert.Error(t, IsGitRepository(filepath.Join(repo, "services")))
+
+	// Linked worktree: .git is a file, the common dir is the main repository's
+	worktree := filepath.Join(dir, "worktree")
+	assert.NoError(t, exec.Command("git", "-C", repo, "worktree", "add", "-q", "-b", "feature", worktree).Run())
+	workspace, err = DiscoverWorkspace(filepath.Join(worktree, "services"))
+	assert.NoError(t, err)
+	assert.Equal(t, &Workspace{Root: worktree, CommonDir: commonDir}, workspace)
+	assert.NoError(t, IsGitRepository(worktree))
+	hooks, err := GitPath(worktree, "hooks")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(commonDir, "hooks"), hooks)
+
+	// Submodule: a repository of its own, stored under the superproject's git dir
+	assert.NoError(t, exec.Command("git", "-C", repo, "-c", "protocol.file.allow=always", "submodule", "add", "-q", repo, "vendor/lib").Run())
+	workspace, err = DiscoverWorkspace(filepath.Join(repo, "vendor", "lib", "services"))
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(repo, "vendor", "lib"), workspace.Root)
+	assert.Equal(t, filepath.Join(commonDir, "modules", "vendor", "lib"), workspace.CommonDir)
+	assert.NoError(t, IsGitRepository(workspace.Root))
+
+	_, err = DiscoverWorkspace(t.TempDir())
+	assert.True(t, errors.Is(err, ErrNotGitRepository), "outside a repository: %v", err)
+	assert.True(t, errors.Is(IsGitRepository(t.TempDir()), ErrNotGitRepository))
+}
+
+func TestGoGitClientAfterRepack(t *testing.T) {
+	dir := t.TempDir()
+	exec.Command("git", "init", "--initial-branch=main", dir).Run()
+	exec.Command("git", "-C", dir, "config", "user.email", "test@example.com").Run()
+	exec.Command("git", "-C", dir, "config", "user.name", "Test User").Run()
+	goGit := NewGoGitClient(nil)
+
+	os.MkdirAll(filepath.Join(dir, "services", "api"), 0755)
+	os.WriteFile(filepath.Join(dir, "services", "api", "file.txt"), []byte("v1\n"), 0644)
+	assert.NoError(t, CommitNoVerify(dir, []string{"."}, "v1"))
+	assert.NoError(t, exec.Command("git", "-C", dir, "gc", "-q", "--prune=now").Run())
+	data, err := goGit.ReadFileFromBranch(dir, "main", "services/api/file.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, "v1\n", string(data))
+
+	// A long-running client (the TUI) must see objects that arrive in a new pack (fetch, repack, gc):
+	// the blob goes into a new pack, the commit and trees on top of it stay loose
+	newFile := filepath.Join(dir, "services", "api", "new.txt")
+	os.WriteFile(newFile, []byte("new\n"), 0644)
+	blob, err := exec.Command("git", "-C", dir, "hash-object", "-w", newFile).Output()
+	assert.NoError(t, err)
+	pack := exec.Command("git", "-C", dir, "pack-objects", "-q", filepath.Join(dir, ".git", "objects", "pack", "pack"))
+	pack.Stdin = bytes.NewReader(blob)
+	assert.NoError(t, pack.Run())
+	assert.NoError(t, exec.Command("git", "-C", dir, "prune-packed").Run())
+	assert.NoError(t, CommitNoVerify(dir, []string{"."}, "v2"))
+
+	data, err = goGit.ReadFileFromBranch(dir, "main", "services/api/new.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, "new\n", string(data))
+	exists, err := goGit.FileExistsInBranch(dir, "main", "services/api/new.txt")
+	assert.NoError(t, err)
+	assert.True(t, exists)
+}
@@ -0,0 +1,185 @@
+package gitUtil
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// GoGitClient answers the hot read-only queries (current branch, branch lookups, files and config
+// read from refs) in process with go-git, without starting git. Every other operation, and any
+// query go-git cannot answer the way git would, goes to the fallback client.
+//
+// Repositories are opened per query: go-git loads the pack indexes once per open repository, so a
+// long-lived handle misses objects that a fetch, repack or gc moved into new packs.
+type GoGitClient struct {
+	GitClient // fallback
+}
+
+// NewGoGitClient returns a go-git backed client. A nil fallback means the exec client.
+func NewGoGitClient(fallback GitClient) *GoGitClient {
+	if fallback == nil {
+		fallback = NewExecClient()
+	}
+	return &GoGitClient{GitClient: fallback}
+}
+
+// NewClient returns the client for a backend name: "exec" (or empty) or "go-git".
+func NewClient(backend string) (GitClient, error) {
+	switch backend {
+	case "", "exec":
+		return NewExecClient(), nil
+	case "go-git":
+		return NewGoGitClient(nil), nil
+	}
+	return nil, fmt.Errorf("unknown git backend '%s' (expected exec or go-git)", backend)
+}
+
+// open returns the repository containing repoPath.
+func (c *GoGitClient) open(repoPath string) (*git.Repository, error) {
+	return git.PlainOpenWithOptions(repoPath, &git.PlainOpenOptions{DetectDotGit: true, EnableDotGitCommonDir: true})
+}
+
+// CurrentBranch reads HEAD without resolving it, so an unborn branch is reported like git does.
+func (c *GoGitClient) CurrentBranch(repoPath string) (string, error) {
+	repo, err := c.open(repoPath)
+	if err != nil {
+		return c.GitClient.CurrentBranch(repoPath)
+	}
+	head, err := repo.Storer.Reference(plumbing.HEAD)
+	if err != nil || head.Type() != plumbing.SymbolicReference || !head.Target().IsBranch() {
+		// Detached HEAD or unusual layout: let git report it
+		return c.GitClient.CurrentBranch(repoPath)
+	}
+	return head.Target().Short(), nil
+}
+
+// RevParse resolves ref names with git's lookup order. Object names and revision expressions
+// (HEAD~1, rev:path, @{u}, ...) go to the fallback.
+func (c *GoGitClient) RevParse(repoPath string, rev string) (string, error) {
+	repo, err := c.open(repoPath)
+	if err != nil {
+		return c.GitClient.RevParse(repoPath, rev)
+	}
+	hash, err := resolveRef(repo, rev)
+	if err == errNotHandled {
+		return c.GitClient.RevParse(repoPath, rev)
+	}
+	if err != nil {
+		return "", fmt.Errorf("cannot resolve revision '%s': %w", rev, err)
+	}
+	return hash.String(), nil
+}
+
+// ReadFileFromBranch reads a file from the tree of a branch (or other ref).
+func (c *GoGitClient) ReadFileFromBranch(repoPath string, branchName string, filePath string) ([]byte, error) {
+	tree, err := c.refTree(repoPath, branchName)
+	if err == errNotHandled {
+		return c.GitClient.ReadFileFromBranch(repoPath, branchName, filePath)
+	}
+	if err == nil {
+		var file *object.File
+		if file, err = tree.File(filepath.ToSlash(filePath)); err == nil {
+			var contents string
+			if contents, err = file.Contents(); err == nil {
+				return []byte(contents), nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("failed to read file '%s' from branch '%s': %w", filePath, branchName, err)
+}
+
+// FileExistsInBranch reports whether a file or directory exists in the tree of a branch.
+func (c *GoGitClient) FileExistsInBranch(repoPath string, branchName string, filePath string) (bool, error) {
+	tree, err := c.refTree(repoPath, branchName)
+	if err == errNotHandled {
+		return c.GitClient.FileExistsInBranch(repoPath, branchName, filePath)
+	}
+	if err != nil {
+		return false, nil
+	}
+	_, err = tree.FindEntry(filepath.ToSlash(filePath))
+	return err == nil, nil
+}
+
+// GetLocalConfig reads a "section.key" or "section.subsection.key" value from the repository config.
+// Returns empty string if not found.
+func (c *GoGitClient) GetLocalConfig(repoPath string, key string) (string, error) {
+	repo, err := c.open(repoPath)
+	if err != nil {
+		return c.GitClient.GetLocalConfig(repoPath, key)
+	}
+	config, err := repo.Config()
+	if err != nil {
+		return c.GitClient.GetLocalConfig(repoPath, key)
+	}
+	first, last := strings.Index(key, "."), strings.LastIndex(key, ".")
+	if first <= 0 || last == len(key)-1 {
+		return "", nil
+	}
+	section, name := key[:first], key[last+1:]
+	if !config.Raw.HasSection(section) {
+		return "", nil
+	}
+	if first == last {
+		return config.Raw.Section(section).Option(name), nil
+	}
+	subsection := key[first+1 : last]
+	if !config.Raw.Section(section).HasSubsection(subsection) {
+		return "", nil
+	}
+	return config.Raw.Section(section).Subsection(subsection).Option(name), nil
+}
+
+// errNotHandled sends a query to the fallback client.
+var errNotHandled = errors.New("not handled by go-git")
+
+// resolveRef looks rev up as a ref the way git does (refs/, refs/tags/, refs/heads/, refs/remotes/).
+// A missing ref yields ErrBranchNotFound.
+func resolveRef(repo *git.Repository, rev string) (plumbing.Hash, error) {
+	if rev == "" || strings.ContainsAny(rev, ":^~@{}[]?* \\") || isHexObjectName(rev) {
+		return plumbing.ZeroHash, errNotHandled
+	}
+	for _, name := range []string{rev, "refs/" + rev, "refs/tags/" + rev, "refs/heads/" + rev, "refs/remotes/" + rev, "refs/remotes/" + rev + "/HEAD"} {
+		if ref, err := repo.Reference(plumbing.ReferenceName(name), true); err == nil {
+			return ref.Hash(), nil
+		}
+	}
+	return plumbing.ZeroHash, ErrBranchNotFound
+}
+
+// refTree returns the tree of the commit a ref points at.
+func (c *GoGitClient) refTree(repoPath string, ref string) (*object.Tree, error) {
+	repo, err := c.open(repoPath)
+	if err != nil {
+		return nil, errNotHandled
+	}
+	hash, err := resolveRef(repo, ref)
+	if err != nil {
+		return nil, err
+	}
+	commit, err := repo.CommitObject(hash)
+	if err != nil {
+		// An annotated tag or a missing object: let git peel or report it
+		return nil, errNotHandled
+	}
+	return commit.Tree()
+}
+
+// isHexObjectName reports whether rev could be an (abbreviated) object name.
+func isHexObjectName(rev string) bool {
+	if len(rev) < 4 || len(rev) > 64 {
+		return false
+	}
+	for _, r := range rev {
+		if !strings.ContainsRune("0123456789abcdef", r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,163 @@
+package groveUtil
+
+import (
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Attribution describes which parts of the monorepo a set of trunk-relative files belongs to.
+type Attribution struct {
+	Repos   map[string][]string // Registered repository name -> files inside it
+	Shared  map[string][]string // Shared path name -> files inside it
+	Neutral []string            // Root files declared neutral for atomicity (neutral_paths)
+	Root    []string            // Remaining files outside every registered repository
+}
+
+// RepoNames returns the affected repository names in lexical order.
+func (a *Attribution) RepoNames() []string {
+	names := make([]string, 0, len(a.Repos))
+	for name := range a.Repos {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SharedNames returns the affected shared path names in lexical order.
+func (a *Attribution) SharedNames() []string {
+	names := make([]string, 0, len(a.Shared))
+	for name := range a.Shared {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// AffectsRoot reports whether any non-neutral root file is involved.
+func (a *Attribution) AffectsRoot() bool {
+	return len(a.Root) > 0
+}
+
+// AttributeFiles maps trunk-relative file paths to the registered repositories that own them.
+// Files outside every repository belong to the first matching shared path (by name). Otherwise
+// they are neutral if they match the global neutral_paths, or the NeutralPaths of a repository
+// touched by the same set of files; the rest count as root.
+func AttributeFiles(config *GGConfig, files []string) *Attribution {
+	attribution := &Attribution{Repos: make(map[string][]string), Shared: make(map[string][]string)}
+
+	var outside []string
+	for _, file := range files {
+		if repoName := OwningRepo(config, file); repoName != "" {
+			attribution.Repos[repoName] = append(attribution.Repos[repoName], file)
+		} else {
+			outside = append(outside, file)
+		}
+	}
+
+	for _, file := range outside {
+		if sharedName := owningSharedPath(config, file); sharedName != "" {
+			attribution.Shared[sharedName] = append(attribution.Shared[sharedName], file)
+		} else if isNeutral(config, attribution.Repos, file) {
+			attribution.Neutral = append(attribution.Neutral, file)
+		} else {
+			attribution.Root = append(attribution.Root, file)
+		}
+	}
+
+	return attribution
+}
+
+// OwningRepo returns the name of the registered repository containing file, or "" if none does.
+func OwningRepo(config *GGConfig, file string) string {
+	for _, repo := range config.Repositories {
+		// We assume repo.Path is relative to root
+		relPath, err := filepath.Rel(repo.Path, file)
+		if err == nil && !strings.HasPrefix(relPath, "..") {
+			return repo.Name
+		}
+	}
+	return ""
+}
+
+func owningSharedPath(config *GGConfig, file string) string {
+	names := make([]string, 0, len(config.SharedPaths))
+	for name := range config.SharedPaths {
+		names = append(names, name)
+	}
+	// Deterministic order in case declarations overlap
+	sort.Strings(names)
+	for _, name := range names {
+		if MatchPath(config.SharedPaths[name].Path, file) {
+			return name
+		}
+	}
+	return ""
+}
+
+func isNeutral(config *GGConfig, affectedRepos map[string][]string, file string) bool {
+	if MatchAnyPath(config.NeutralPaths, file) {
+		return true
+	}
+	for repoName := range affectedRepos {
+		if MatchAnyPath(config.Repositories[repoName].NeutralPaths, file) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchAnyPath reports whether file matches any of patterns (see MatchPath).
+func MatchAnyPath(patterns []string, file string) bool {
+	for _, pattern := range patterns {
+		if MatchPath(pattern, file) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchPath reports whether the slash-separated, root-relative file matches pattern.
+// Patterns are either plain paths, matching the path itself and everything below it
+// (e.g. "go.work", ".github/workflows"), or globs where "*" and "?" stay within one path
+// segment and "**" spans directories (e.g. "deploy/*.yaml", "**/CODEOWNERS").
+func MatchPath(pattern string, file string) bool {
+	pattern = strings.TrimSuffix(filepath.ToSlash(strings.TrimPrefix(pattern, "./")), "/")
+	file = filepath.ToSlash(file)
+	if pattern == "" {
+		return false
+	}
+
+	re, err := regexp.Compile("^" + globToRegexp(pattern) + "(/.*)?$")
+	if err != nil {
+		return false
+	}
+	return re.MatchString(file)
+}
+
+func globToRegexp(pattern string) string {
+	var b strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				i++
+				if i+1 < len(pattern) && pattern[i+1] == '/' {
+					// "**/" matches zero or more leading directories
+					i++
+					b.WriteString("(.*/)?")
+				} else {
+					b.WriteString(".*")
+				}
+			} else {
+				b.WriteString("[^/]*")
+			}
+		case '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return b.String()
+}
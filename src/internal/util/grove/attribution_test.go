@@ -0,0 +1,82 @@
+package groveUtil_test
+
+import (
+	"reflect"
+	"testing"
+
+	groveUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/grove"
+	"github.com/kuchuk-borom-debbarma/GitGrove/src/model"
+)
+
+func TestMatchPath(t *testing.T) {
+	cases := []struct {
+		pattern, file string
+		want          bool
+	}{
+		{"go.work", "go.work", true},
+		{"go.work", "go.work.sum", false},
+		{".github/workflows", ".github/workflows/ci.yaml", true},
+		{"./docs/", "docs/index.md", true},
+		{"deploy/*.yaml", "deploy/app.yaml", true},
+		{"deploy/*.yaml", "deploy/prod/app.yaml", false},
+		{"**/CODEOWNERS", "CODEOWNERS", true},
+		{"**/CODEOWNERS", "services/a/CODEOWNERS", true},
+		{"proto/**", "proto/v1/api.proto", true},
+		{"file?.txt", "file1.txt", true},
+		{"file?.txt", "file/.txt", false},
+		{"", "anything", false},
+	}
+	for _, c := range cases {
+		if got := groveUtil.MatchPath(c.pattern, c.file); got != c.want {
+			t.Errorf("MatchPath(%q, %q) = %v, want %v", c.pattern, c.file, got, c.want)
+		}
+	}
+}
+
+func TestAttributeFiles(t *testing.T) {
+	config := &groveUtil.GGConfig{
+		Repositories: map[string]model.GGRepo{
+			"billing": {Name: "billing", Path: "services/billing", NeutralPaths: []string{"CODEOWNERS"}},
+			"search":  {Name: "search", Path: "services/search"},
+		},
+		NeutralPaths: []string{"go.work"},
+		SharedPaths:  map[string]model.SharedPath{"proto": {Name: "proto", Path: "proto"}},
+	}
+
+	attribution := groveUtil.AttributeFiles(config, []string{
+		"services/billing/main.go",
+		"services/billing/api/handler.go",
+		"services/search/main.go",
+		"proto/billing.proto",
+		"go.work",
+		"CODEOWNERS",
+		"README.md",
+	})
+
+	if got := attribution.RepoNames(); !reflect.DeepEqual(got, []string{"billing", "search"}) {
+		t.Errorf("unexpected repos: %v", got)
+	}
+	if got := attribution.Repos["billing"]; len(got) != 2 {
+		t.Errorf("expected both billing files, got %v", got)
+	}
+	if got := attribution.SharedNames(); !reflect.DeepEqual(got, []string{"proto"}) {
+		t.Errorf("unexpected shared paths: %v", got)
+	}
+	// go.work is globally neutral, CODEOWNERS through billing's NeutralPaths
+	if !reflect.DeepEqual(attribution.Neutral, []string{"go.work", "CODEOWNERS"}) {
+		t.Errorf("unexpected neutral files: %v", attribution.Neutral)
+	}
+	if !attribution.AffectsRoot() || !reflect.DeepEqual(attribution.Root, []string{"README.md"}) {
+		t.Errorf("unexpected root files: %v", attribution.Root)
+	}
+
+	// A repo's neutral paths only apply when that repo is part of the change
+	attribution = groveUtil.AttributeFiles(config, []string{"services/search/main.go", "CODEOWNERS"})
+	if !reflect.DeepEqual(attribution.Root, []string{"CODEOWNERS"}) {
+		t.Errorf("expected CODEOWNERS to count as root without billing, got %+v", attribution)
+	}
+
+	if repo := groveUtil.OwningRepo(config, "services/billing-old/main.go"); repo != "" {
+		t.Errorf("expected no owner for a sibling path, got %q", repo)
+	}
+}
@@ -0,0 +1,100 @@
+package groveUtil
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	gitUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/git"
+)
+
+// CheckResult is the outcome of a single pre-integration check command.
+type CheckResult struct {
+	Command  string        `json:"command"`
+	Passed   bool          `json:"passed"`
+	ExitCode int           `json:"exit_code"`
+	Duration time.Duration `json:"duration"`
+	Output   string        `json:"output,omitempty"` // Tail of combined stdout/stderr
+}
+
+// CheckReport records the checks run on a prepare-merge branch.
+type CheckReport struct {
+	Repo    string        `json:"repo"`
+	Branch  string        `json:"branch"`
+	RanAt   time.Time     `json:"ran_at"`
+	Passed  bool          `json:"passed"`
+	Results []CheckResult `json:"results"`
+}
+
+// Summary returns a one-line description of the report (e.g. "checks passed (2/2)").
+func (r *CheckReport) Summary() string {
+	passed := 0
+	for _, res := range r.Results {
+		if res.Passed {
+			passed++
+		}
+	}
+	status := "passed"
+	if !r.Passed {
+		status = "FAILED"
+	}
+	return fmt.Sprintf("checks %s (%d/%d)", status, passed, len(r.Results))
+}
+
+// CheckReportPath returns .git/gg/checks/<branch>.json. Reports live in the common git dir so they never
+// get committed and every worktree sees the report of a branch.
+func CheckReportPath(git gitUtil.GitClient, ggRepoPath string, branch string) (string, error) {
+	workspace, err := git.DiscoverWorkspace(ggRepoPath)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(workspace.CommonDir, "gg", "checks", filepath.FromSlash(branch)+".json"), nil
+}
+
+// SaveCheckReport stores the check report for its branch.
+func SaveCheckReport(git gitUtil.GitClient, ggRepoPath string, report *CheckReport) error {
+	reportPath, err := CheckReportPath(git, ggRepoPath, report.Branch)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(reportPath), 0755); err != nil {
+		return fmt.Errorf("failed to create check report directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal check report: %w", err)
+	}
+	if err := os.WriteFile(reportPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write check report: %w", err)
+	}
+	return nil
+}
+
+// LoadCheckReport reads the check report for a branch. Returns nil (and no error) if no checks were recorded.
+func LoadCheckReport(git gitUtil.GitClient, ggRepoPath string, branch string) (*CheckReport, error) {
+	reportPath, err := CheckReportPath(git, ggRepoPath, branch)
+	if err != nil {
+		return nil, err
+	}
+	return ReadCheckReport(reportPath)
+}
+
+// ReadCheckReport reads a check report from its CheckReportPath. Returns nil (and no error) if it does not exist.
+func ReadCheckReport(reportPath string) (*CheckReport, error) {
+	data, err := os.ReadFile(reportPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read check report: %w", err)
+	}
+
+	var report CheckReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse check report: %w", err)
+	}
+	return &report, nil
+}
@@ -0,0 +1,54 @@
+package groveUtil_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kuchuk-borom-debbarma/GitGrove/src/internal/testutil"
+	gitUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/git"
+	groveUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/grove"
+)
+
+func TestCheckReport(t *testing.T) {
+	git := gitUtil.NewExecClient()
+	dir := testutil.GitRepo(t)
+	branch := "gg/merge-prep/billing/20260101-120000"
+
+	// Nothing recorded yet
+	if report, err := groveUtil.LoadCheckReport(git, dir, branch); err != nil || report != nil {
+		t.Fatalf("expected no report, got %+v (%v)", report, err)
+	}
+
+	report := &groveUtil.CheckReport{
+		Repo:   "billing",
+		Branch: branch,
+		RanAt:  time.Now().UTC().Truncate(time.Second),
+		Passed: false,
+		Results: []groveUtil.CheckResult{
+			{Command: "go vet ./...", Passed: true},
+			{Command: "go test ./...", Passed: false, ExitCode: 1, Output: "FAIL"},
+		},
+	}
+	if err := groveUtil.SaveCheckReport(git, dir, report); err != nil {
+		t.Fatalf("SaveCheckReport failed: %v", err)
+	}
+
+	// Stored in the git dir, keyed by the branch name
+	reportPath, _ := groveUtil.CheckReportPath(git, dir, branch)
+	if !strings.HasPrefix(reportPath, filepath.Join(dir, ".git", "gg", "checks")) {
+		t.Errorf("unexpected report path: %s", reportPath)
+	}
+
+	loaded, err := groveUtil.LoadCheckReport(git, dir, branch)
+	if err != nil || loaded == nil {
+		t.Fatalf("LoadCheckReport failed: %v", err)
+	}
+	if loaded.Repo != "billing" || !loaded.RanAt.Equal(report.RanAt) || len(loaded.Results) != 2 || loaded.Results[1].Output != "FAIL" {
+		t.Errorf("unexpected report: %+v", loaded)
+	}
+	if got := loaded.Summary(); got != "checks FAILED (1/2)" {
+		t.Errorf("unexpected summary: %q", got)
+	}
+}
@@ -0,0 +1,25 @@
+package groveUtil
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Workspace errors, matched with errors.Is. Git failures wrap the sentinels of gitUtil
+// (ErrNotGitRepository, ErrBranchNotFound, ErrDirtyWorktree, ErrMergeConflict).
+var (
+	ErrNotInitialized     = errors.New("gitgrove is not initialized")
+	ErrAlreadyInitialized = errors.New("gitgrove is already initialized")
+	ErrRepoNotRegistered  = errors.New("repository not registered in gitgrove")
+)
+
+// RepoNotRegisteredError reports a repository name missing from gg.json. It matches ErrRepoNotRegistered.
+type RepoNotRegisteredError struct {
+	Name string
+}
+
+func (e *RepoNotRegisteredError) Error() string {
+	return fmt.Sprintf("repository '%s' not registered in gitgrove", e.Name)
+}
+
+func (e *RepoNotRegisteredError) Is(target error) bool { return target == ErrRepoNotRegistered }
@@ -7,31 +7,83 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/kuchuk-borom-debbarma/GitGrove/src/internal/forge"
 	gitUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/git"
 	"github.com/kuchuk-borom-debbarma/GitGrove/src/model"
 )
 
-// IsGroveInitialized checks if the .gg directory and configuration file exist.
-func IsGroveInitialized(path string) error {
+// InitState tells how a path belongs to an initialized workspace.
+type InitState int
+
+const (
+	NotInitialized     InitState = iota
+	InitializedTrunk             // .gg/gg.json is in the working tree
+	InitializedOrphan            // on an orphan branch of a trunk that has .gg/gg.json
+	InitializedContext           // sticky context points at an orphan branch
+)
+
+// InitStatus is the result of IsGroveInitialized.
+type InitStatus struct {
+	State  InitState
+	Detail string // the orphan branch's trunk or the context's orphan branch
+}
+
+// Initialized reports whether the path is part of an initialized workspace.
+func (s InitStatus) Initialized() bool { return s.State != NotInitialized }
+
+// AlreadyInitializedError returns ErrAlreadyInitialized describing the status, or nil if not initialized.
+func (s InitStatus) AlreadyInitializedError(path string) error {
+	switch s.State {
+	case InitializedTrunk:
+		return fmt.Errorf("%w in %s", ErrAlreadyInitialized, path)
+	case InitializedOrphan:
+		return fmt.Errorf("%w (orphan branch of %s)", ErrAlreadyInitialized, s.Detail)
+	case InitializedContext:
+		return fmt.Errorf("%w (sticky context: %s)", ErrAlreadyInitialized, s.Detail)
+	}
+	return nil
+}
+
+// WorkspaceRoot returns the root of the working tree containing path, so commands work from any
+// subdirectory, linked worktree or submodule. Outside a repository path is returned unchanged.
+func WorkspaceRoot(git gitUtil.GitClient, path string) string {
+	workspace, err := git.DiscoverWorkspace(path)
+	if err != nil {
+		return path
+	}
+	return workspace.Root
+}
+
+// HasTrunkTree reports whether root has a trunk tree checked out (.gg/gg.json on disk). There, paths
+// are trunk-relative and files are attributed to repositories by path; a sticky context only names the
+// repository on branches without gg.json (orphan and feature-of-orphan branches).
+func HasTrunkTree(root string) bool {
+	_, err := os.Stat(filepath.Join(root, ".gg", "gg.json"))
+	return err == nil
+}
+
+// IsGroveInitialized checks if the .gg directory and configuration file exist, either in the
+// working tree or on the trunk of the current orphan branch or sticky context.
+func IsGroveInitialized(git gitUtil.GitClient, path string) (InitStatus, error) {
 	// 1. Check local file system
 	configPath := filepath.Join(path, ".gg", "gg.json")
 	if _, err := os.Stat(configPath); err == nil {
-		return fmt.Errorf("gitgrove is already initialized in %s", path)
+		return InitStatus{State: InitializedTrunk}, nil
 	} else if !os.IsNotExist(err) {
-		return fmt.Errorf("error checking grove initialization: %w", err)
+		return InitStatus{}, fmt.Errorf("error checking grove initialization: %w", err)
 	}
 
 	// 2. Check if we are in an orphan branch (gg/<trunk>/<repoName>)
 	// If so, check if .gg/gg.json exists in <trunk>
-	if err := gitUtil.IsGitRepository(path); err != nil {
+	if err := git.IsGitRepository(path); err != nil {
 		// Not a git repo, so definitely not initialized
-		return nil
+		return InitStatus{}, nil
 	}
 
-	currentBranch, err := gitUtil.CurrentBranch(path)
+	currentBranch, err := git.CurrentBranch(path)
 	if err != nil {
 		// Ignore error, maybe no commits yet
-		return nil
+		return InitStatus{}, nil
 	}
 
 	// Pattern: gg/<trunk>/<repoName>
@@ -63,9 +115,9 @@ func IsGroveInitialized(path string) error {
 		// Because last part is repoName.
 		for i := 1; i < len(parts)-1; i++ {
 			trunkCandidate := strings.Join(parts[1:i+1], "/")
-			exists, err := gitUtil.FileExistsInBranch(path, trunkCandidate, ".gg/gg.json")
+			exists, err := git.FileExistsInBranch(path, trunkCandidate, ".gg/gg.json")
 			if err == nil && exists {
-				return fmt.Errorf("gitgrove is already initialized (orphan branch of %s)", trunkCandidate)
+				return InitStatus{State: InitializedOrphan, Detail: trunkCandidate}, nil
 			}
 		}
 	}
@@ -74,12 +126,12 @@ func IsGroveInitialized(path string) error {
 	// If we are deep in a feature branch off an orphan, we might not have .gg/gg.json
 	// AND the branch name might not mismatch gg/...
 	// But we might have sticky context set.
-	orphanContext, err := GetContextOrphan(path)
+	orphanContext, err := GetContextOrphan(git, path)
 	if err == nil && orphanContext != "" {
-		return fmt.Errorf("gitgrove is already initialized (sticky context: %s)", orphanContext)
+		return InitStatus{State: InitializedContext, Detail: orphanContext}, nil
 	}
 
-	return nil
+	return InitStatus{}, nil
 }
 
 // CreateGroveConfig creates the .gg directory and the gg.json file.
@@ -110,8 +162,13 @@ func CreateGroveConfig(path string, repoAwareContextMessage bool) error {
 
 // GGConfig represents the structure of gg.json
 type GGConfig struct {
-	Repositories            map[string]model.GGRepo `json:"repositories"`
-	RepoAwareContextMessage bool                    `json:"repo_aware_context_message"`
+	Repositories            map[string]model.GGRepo     `json:"repositories"`
+	RepoAwareContextMessage bool                        `json:"repo_aware_context_message"`
+	Forge                   *forge.Config               `json:"forge,omitempty"`
+	IntegrationStrategy     string                      `json:"integration_strategy,omitempty"` // "merge" (default) or "replay"
+	NeutralPaths            []string                    `json:"neutral_paths,omitempty"`        // Root paths/globs that never count as root files for atomicity (e.g. "go.work")
+	SharedPaths             map[string]model.SharedPath `json:"shared_paths,omitempty"`         // Cross-cutting paths with their own commit policy
+	ProtectTrunk            bool                        `json:"protect_trunk,omitempty"`        // Reject direct trunk commits to registered paths
 }
 
 // LoadConfig reads the gg.json configuration from the .gg directory.
@@ -135,9 +192,9 @@ func LoadConfig(ggRootPath string) (*GGConfig, error) {
 }
 
 // LoadConfigFromGitRef reads the gg.json configuration from a specific git reference (branch/commit).
-func LoadConfigFromGitRef(ggRootPath string, ref string) (*GGConfig, error) {
+func LoadConfigFromGitRef(git gitUtil.GitClient, ggRootPath string, ref string) (*GGConfig, error) {
 	configPath := ".gg/gg.json"
-	data, err := gitUtil.ReadFileFromBranch(ggRootPath, ref, configPath)
+	data, err := git.ReadFileFromBranch(ggRootPath, ref, configPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read gg.json from ref %s: %w", ref, err)
 	}
@@ -154,6 +211,21 @@ func LoadConfigFromGitRef(ggRootPath string, ref string) (*GGConfig, error) {
 	return &config, nil
 }
 
+// LoadWorkspaceConfig loads gg.json from the working tree, falling back to the trunk's committed copy
+// when working on an orphan or feature branch where the file is not checked out.
+func LoadWorkspaceConfig(git gitUtil.GitClient, ggRootPath string) (*GGConfig, error) {
+	config, err := LoadConfig(ggRootPath)
+	if err == nil {
+		return config, nil
+	}
+	if trunk, _ := ResolveRepoContext(git, ggRootPath); trunk != "" {
+		if branchConfig, branchErr := LoadConfigFromGitRef(git, ggRootPath, trunk); branchErr == nil {
+			return branchConfig, nil
+		}
+	}
+	return nil, err
+}
+
 // RegisterRepoInConfig adds new repositories to the gg.json configuration.
 // It performs validation to ensure no name/path conflicts or nested repositories.
 func RegisterRepoInConfig(ggRootPath string, newRepos []model.GGRepo) error {
@@ -213,6 +285,13 @@ func ValidateRepoRegistration(ggRootPath string, config *GGConfig, newRepos []mo
 				return fmt.Errorf("cannot register '%s' which contains existing repo '%s'", cleanedPath, existingRepo.Path)
 			}
 		}
+
+		// Shared paths belong to no repository
+		for _, shared := range config.SharedPaths {
+			if MatchPath(shared.Path, filepath.ToSlash(cleanedPath)) {
+				return fmt.Errorf("cannot register '%s' inside shared path '%s' (%s)", cleanedPath, shared.Name, shared.Path)
+			}
+		}
 	}
 	return nil
 }
@@ -247,54 +326,111 @@ func AddReposToConfig(ggRootPath string, newRepos []model.GGRepo) error {
 }
 
 // SetContextRepo sets the gitgrove.context.repo config to the specified repository name.
-func SetContextRepo(ggRepoPath string, repoName string) error {
-	return gitUtil.SetLocalConfig(ggRepoPath, "gitgrove.context.repo", repoName)
+func SetContextRepo(git gitUtil.GitClient, ggRepoPath string, repoName string) error {
+	return git.SetLocalConfig(ggRepoPath, "gitgrove.context.repo", repoName)
 }
 
 // GetContextRepo gets the repository name from gitgrove.context.repo config.
-func GetContextRepo(ggRepoPath string) (string, error) {
-	return gitUtil.GetLocalConfig(ggRepoPath, "gitgrove.context.repo")
+func GetContextRepo(git gitUtil.GitClient, ggRepoPath string) (string, error) {
+	return git.GetLocalConfig(ggRepoPath, "gitgrove.context.repo")
 }
 
 // ClearContextRepo removes the gitgrove.context.repo config.
-func ClearContextRepo(ggRepoPath string) error {
-	return gitUtil.UnsetLocalConfig(ggRepoPath, "gitgrove.context.repo")
+func ClearContextRepo(git gitUtil.GitClient, ggRepoPath string) error {
+	return git.UnsetLocalConfig(ggRepoPath, "gitgrove.context.repo")
 }
 
 // SetContextTrunk sets the gitgrove.context.trunk config to the specified branch name.
-func SetContextTrunk(ggRepoPath string, trunkName string) error {
-	return gitUtil.SetLocalConfig(ggRepoPath, "gitgrove.context.trunk", trunkName)
+func SetContextTrunk(git gitUtil.GitClient, ggRepoPath string, trunkName string) error {
+	return git.SetLocalConfig(ggRepoPath, "gitgrove.context.trunk", trunkName)
 }
 
 // GetContextTrunk gets the trunk branch name from gitgrove.context.trunk config.
-func GetContextTrunk(ggRepoPath string) (string, error) {
-	return gitUtil.GetLocalConfig(ggRepoPath, "gitgrove.context.trunk")
+func GetContextTrunk(git gitUtil.GitClient, ggRepoPath string) (string, error) {
+	return git.GetLocalConfig(ggRepoPath, "gitgrove.context.trunk")
 }
 
 // ClearContextTrunk removes the gitgrove.context.trunk config.
-func ClearContextTrunk(ggRepoPath string) error {
-	return gitUtil.UnsetLocalConfig(ggRepoPath, "gitgrove.context.trunk")
+func ClearContextTrunk(git gitUtil.GitClient, ggRepoPath string) error {
+	return git.UnsetLocalConfig(ggRepoPath, "gitgrove.context.trunk")
 }
 
 // SetContextOrphan sets the gitgrove.context.orphan config to the specified orphan branch name.
-func SetContextOrphan(ggRepoPath string, orphanBranch string) error {
-	return gitUtil.SetLocalConfig(ggRepoPath, "gitgrove.context.orphan", orphanBranch)
+func SetContextOrphan(git gitUtil.GitClient, ggRepoPath string, orphanBranch string) error {
+	return git.SetLocalConfig(ggRepoPath, "gitgrove.context.orphan", orphanBranch)
 }
 
 // GetContextOrphan gets the orphan branch name from gitgrove.context.orphan config.
-func GetContextOrphan(ggRepoPath string) (string, error) {
-	return gitUtil.GetLocalConfig(ggRepoPath, "gitgrove.context.orphan")
+func GetContextOrphan(git gitUtil.GitClient, ggRepoPath string) (string, error) {
+	return git.GetLocalConfig(ggRepoPath, "gitgrove.context.orphan")
 }
 
 // ClearContextOrphan removes the gitgrove.context.orphan config.
-func ClearContextOrphan(ggRepoPath string) error {
-	return gitUtil.UnsetLocalConfig(ggRepoPath, "gitgrove.context.orphan")
+func ClearContextOrphan(git gitUtil.GitClient, ggRepoPath string) error {
+	return git.UnsetLocalConfig(ggRepoPath, "gitgrove.context.orphan")
+}
+
+// SetContextScope sets the gitgrove.context.scope config (the active scope lock) to the specified repository name.
+func SetContextScope(git gitUtil.GitClient, ggRepoPath string, repoName string) error {
+	return git.SetLocalConfig(ggRepoPath, "gitgrove.context.scope", repoName)
+}
+
+// GetContextScope gets the active scope from gitgrove.context.scope config.
+func GetContextScope(git gitUtil.GitClient, ggRepoPath string) (string, error) {
+	return git.GetLocalConfig(ggRepoPath, "gitgrove.context.scope")
 }
 
-// ClearAllContext removes all gitgrove context configs.
-func ClearAllContext(ggRepoPath string) error {
-	_ = ClearContextRepo(ggRepoPath)
-	_ = ClearContextTrunk(ggRepoPath)
-	_ = ClearContextOrphan(ggRepoPath)
+// ClearContextScope removes the gitgrove.context.scope config.
+func ClearContextScope(git gitUtil.GitClient, ggRepoPath string) error {
+	return git.UnsetLocalConfig(ggRepoPath, "gitgrove.context.scope")
+}
+
+// ClearAllContext removes all gitgrove checkout context configs.
+// The scope lock is deliberately kept; it is only removed by ClearContextScope (gg scope --clear).
+func ClearAllContext(git gitUtil.GitClient, ggRepoPath string) error {
+	_ = ClearContextRepo(git, ggRepoPath)
+	_ = ClearContextTrunk(git, ggRepoPath)
+	_ = ClearContextOrphan(git, ggRepoPath)
 	return nil
 }
+
+// ParseOrphanBranch splits an orphan branch name gg/<trunk>/<repoName> into its trunk and repo name.
+// Returns ok=false for other branches (including gg/merge-prep/...).
+func ParseOrphanBranch(branch string) (trunk string, repoName string, ok bool) {
+	parts := strings.Split(branch, "/")
+	if len(parts) < 3 || parts[0] != "gg" || parts[1] == "merge-prep" {
+		return "", "", false
+	}
+	return strings.Join(parts[1:len(parts)-1], "/"), parts[len(parts)-1], true
+}
+
+// ResolveRepoContext determines the trunk and repository the user is working on:
+// the sticky context first, then the orphan branch name. Either value may be empty if unknown.
+func ResolveRepoContext(git gitUtil.GitClient, ggRepoPath string) (trunk string, repoName string) {
+	trunk, _ = GetContextTrunk(git, ggRepoPath)
+	repoName, _ = GetContextRepo(git, ggRepoPath)
+	if trunk != "" && repoName != "" {
+		return trunk, repoName
+	}
+
+	if currentBranch, err := git.CurrentBranch(ggRepoPath); err == nil {
+		if branchTrunk, branchRepo, ok := ParseOrphanBranch(currentBranch); ok {
+			if trunk == "" {
+				trunk = branchTrunk
+			}
+			if repoName == "" {
+				repoName = branchRepo
+			}
+		}
+	}
+	return trunk, repoName
+}
+
+// IsTrunkProtected reports whether direct trunk commits to the repository are forbidden:
+// the repository's ProtectTrunk setting if present, otherwise the global protect_trunk.
+func IsTrunkProtected(config *GGConfig, repoName string) bool {
+	if repo, ok := config.Repositories[repoName]; ok && repo.ProtectTrunk != nil {
+		return *repo.ProtectTrunk
+	}
+	return config.ProtectTrunk
+}
@@ -0,0 +1,33 @@
+package groveUtil_test
+
+import (
+	"testing"
+
+	gitUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/git"
+	groveUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/grove"
+)
+
+func TestResolveRepoContext(t *testing.T) {
+	git := gitUtil.NewFakeClient()
+
+	// The orphan branch name fills in what the sticky context lacks
+	git.Head = "gg/main/billing"
+	if trunk, repo := groveUtil.ResolveRepoContext(git, "/workspace"); trunk != "main" || repo != "billing" {
+		t.Errorf("expected main/billing from the branch name, got %s/%s", trunk, repo)
+	}
+
+	// The sticky context wins on feature branches
+	git.Head = "feature/login"
+	groveUtil.SetContextRepo(git, "/workspace", "search")
+	groveUtil.SetContextTrunk(git, "/workspace", "develop")
+	if trunk, repo := groveUtil.ResolveRepoContext(git, "/workspace"); trunk != "develop" || repo != "search" {
+		t.Errorf("expected develop/search from the sticky context, got %s/%s", trunk, repo)
+	}
+
+	// Merge-prep branches do not name a repository
+	groveUtil.ClearAllContext(git, "/workspace")
+	git.Head = "gg/merge-prep/billing/20260101-120000"
+	if trunk, repo := groveUtil.ResolveRepoContext(git, "/workspace"); trunk != "" || repo != "" {
+		t.Errorf("expected no context on a merge-prep branch, got %s/%s", trunk, repo)
+	}
+}
@@ -0,0 +1,150 @@
+package groveUtil
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	gitUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/git"
+)
+
+// Trailers written on trunk commits produced by an integration (subtree merge commit or replayed commit).
+const (
+	RepoTrailer         = "GG-Repo"
+	OrphanCommitTrailer = "GG-Orphan-Commit"
+)
+
+// IntegrationRecord describes an orphan commit that has been integrated into the trunk.
+type IntegrationRecord struct {
+	Repo         string    `json:"repo"`
+	OrphanCommit string    `json:"orphan_commit"` // SHA on the orphan branch
+	TrunkCommit  string    `json:"trunk_commit"`  // SHA of the trunk commit carrying the trailers
+	Date         time.Time `json:"date"`          // Committer date of the trunk commit
+}
+
+// WithIntegrationTrailers adds the trailers recording that orphanCommit of repoName was integrated to
+// message. Trailers the message already has (Signed-off-by, Co-authored-by, ...) are kept in the block.
+func WithIntegrationTrailers(git gitUtil.GitClient, ggRepoPath string, message string, repoName string, orphanCommit string) (string, error) {
+	return git.AppendTrailers(ggRepoPath, message,
+		fmt.Sprintf("%s: %s", RepoTrailer, repoName),
+		fmt.Sprintf("%s: %s", OrphanCommitTrailer, orphanCommit))
+}
+
+// IntegrationHistory returns the integrations of repoName recorded on trunkRef, most recent first.
+func IntegrationHistory(git gitUtil.GitClient, ggRepoPath string, trunkRef string, repoName string) ([]IntegrationRecord, error) {
+	format := fmt.Sprintf("--format=%%H%%x00%%cI%%x00%%(trailers:key=%s,valueonly,separator=%%x2C)%%x00%%(trailers:key=%s,valueonly,separator=%%x2C)%%x1e",
+		RepoTrailer, OrphanCommitTrailer)
+	output, err := git.Log(ggRepoPath, format, "--grep="+OrphanCommitTrailer+":", trunkRef)
+	if err != nil {
+		return nil, err
+	}
+
+	records := []IntegrationRecord{}
+	for _, entry := range strings.Split(output, "\x1e") {
+		fields := strings.Split(strings.TrimSpace(entry), "\x00")
+		if len(fields) != 4 || fields[3] == "" {
+			continue
+		}
+		if strings.TrimSpace(fields[2]) != repoName {
+			continue
+		}
+		date, _ := time.Parse(time.RFC3339, fields[1])
+		for _, orphanCommit := range strings.Split(fields[3], ",") {
+			records = append(records, IntegrationRecord{
+				Repo:         repoName,
+				OrphanCommit: strings.TrimSpace(orphanCommit),
+				TrunkCommit:  fields[0],
+				Date:         date,
+			})
+		}
+	}
+	return records, nil
+}
+
+// LastIntegration returns the most recent integration of repoName on trunkRef, or nil if it was never integrated.
+func LastIntegration(git gitUtil.GitClient, ggRepoPath string, trunkRef string, repoName string) (*IntegrationRecord, error) {
+	records, err := IntegrationHistory(git, ggRepoPath, trunkRef, repoName)
+	if err != nil || len(records) == 0 {
+		return nil, err
+	}
+	return &records[0], nil
+}
+
+// IntegrationBase returns the orphan commit up to which orphanRef's history is already on trunkRef.
+// Its tree is what the trunk's copy of repoPath last matched, which makes it the merge base of an integration.
+//
+// It is the most recent recorded integration that is still an ancestor of orphanRef. If there is none
+// (never integrated, or the orphan branch was reset since), it is the newest commit of the trunk's own
+// (deterministic) subtree split of repoPath that orphanRef contains: the trunk state the orphan branch
+// was created (or reset) from. Returns an empty string if neither can be determined.
+func IntegrationBase(git gitUtil.GitClient, ggRepoPath string, trunkRef string, orphanRef string, repoName string, repoPath string) (string, error) {
+	base, err := RecordedIntegrationBase(git, ggRepoPath, trunkRef, orphanRef, repoName)
+	if err != nil || base != "" {
+		return base, err
+	}
+	split, err := git.SubtreeSplitRev(ggRepoPath, repoPath, trunkRef)
+	if err != nil {
+		return "", nil
+	}
+	return SplitIntegrationBase(git, ggRepoPath, split, orphanRef), nil
+}
+
+// RecordedIntegrationBase is the part of IntegrationBase read from the trunk's trailers. Returns an empty
+// string if no recorded integration is an ancestor of orphanRef.
+func RecordedIntegrationBase(git gitUtil.GitClient, ggRepoPath string, trunkRef string, orphanRef string, repoName string) (string, error) {
+	records, err := IntegrationHistory(git, ggRepoPath, trunkRef, repoName)
+	if err != nil {
+		return "", err
+	}
+	for _, record := range records {
+		if git.IsAncestor(ggRepoPath, record.OrphanCommit, orphanRef) {
+			return record.OrphanCommit, nil
+		}
+	}
+	return "", nil
+}
+
+// SplitIntegrationBase is the fallback of IntegrationBase for a subtree split of the trunk computed by the
+// caller (git.SubtreeSplitRev). Returns an empty string for unrelated histories.
+func SplitIntegrationBase(git gitUtil.GitClient, ggRepoPath string, split string, orphanRef string) string {
+	base, err := git.MergeBase(ggRepoPath, split, orphanRef)
+	if err != nil {
+		return ""
+	}
+	return base
+}
+
+// PendingCommits lists the orphan commits of repoName not yet integrated into trunkRef, oldest first.
+// Merge commits made on the orphan branch are included.
+func PendingCommits(git gitUtil.GitClient, ggRepoPath string, trunkRef string, orphanRef string, repoName string, repoPath string) ([]string, error) {
+	base, err := IntegrationBase(git, ggRepoPath, trunkRef, orphanRef, repoName, repoPath)
+	if err != nil {
+		return nil, err
+	}
+	return PendingCommitsSince(git, ggRepoPath, trunkRef, orphanRef, base)
+}
+
+// PendingCommitsSince is PendingCommits for a known IntegrationBase (empty: the whole orphan history).
+func PendingCommitsSince(git gitUtil.GitClient, ggRepoPath string, trunkRef string, orphanRef string, base string) ([]string, error) {
+	// Commits reachable from the trunk were brought in by a subtree merge.
+	args := []string{"--reverse", "--topo-order", orphanRef, "--not", trunkRef}
+	if base != "" {
+		args = append(args, base)
+	}
+	return git.RevList(ggRepoPath, args...)
+}
+
+// TrunkOnlyCommits lists the trunk commits to repoPath that are not on orphanRef yet, i.e. what a reset of the
+// orphan branch would bring in. Integration commits (which carry orphan history back) are not counted.
+func TrunkOnlyCommits(git gitUtil.GitClient, ggRepoPath string, trunkRef string, orphanRef string, repoPath string) ([]string, error) {
+	split, err := git.SubtreeSplitRev(ggRepoPath, repoPath, trunkRef)
+	if err != nil {
+		return nil, err
+	}
+	return TrunkOnlyCommitsOfSplit(git, ggRepoPath, split, orphanRef)
+}
+
+// TrunkOnlyCommitsOfSplit is TrunkOnlyCommits for a subtree split of the trunk computed by the caller.
+func TrunkOnlyCommitsOfSplit(git gitUtil.GitClient, ggRepoPath string, split string, orphanRef string) ([]string, error) {
+	return git.RevList(ggRepoPath, "--no-merges", "--invert-grep", "--grep="+OrphanCommitTrailer+":", split, "--not", orphanRef)
+}
@@ -0,0 +1,143 @@
+package groveUtil_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kuchuk-borom-debbarma/GitGrove/src/internal/testutil"
+	gitUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/git"
+	groveUtil "github.com/kuchuk-borom-debbarma/GitGrove/src/internal/util/grove"
+)
+
+func TestIntegrationBase(t *testing.T) {
+	git := gitUtil.NewExecClient()
+	dir := testutil.TwoRepoWorkspace(t)
+	orphan := "gg/main/repoA"
+	root := testutil.Git(t, dir, "rev-parse", orphan)
+
+	base := func() string {
+		t.Helper()
+		base, err := groveUtil.IntegrationBase(git, dir, "main", orphan, "repoA", "services/repoA")
+		if err != nil {
+			t.Fatalf("IntegrationBase failed: %v", err)
+		}
+		return base
+	}
+	pending := func() []string {
+		t.Helper()
+		commits, err := groveUtil.PendingCommits(git, dir, "main", orphan, "repoA", "services/repoA")
+		if err != nil {
+			t.Fatalf("PendingCommits failed: %v", err)
+		}
+		return commits
+	}
+	orphanCommit := func(file, content string) string {
+		t.Helper()
+		testutil.WriteFile(t, dir, file, content)
+		testutil.Git(t, dir, "add", file)
+		testutil.Git(t, dir, "commit", "-q", "-m", "Edit "+file)
+		return testutil.Git(t, dir, "rev-parse", "HEAD")
+	}
+	recordIntegration := func(orphanCommit string) {
+		t.Helper()
+		message, err := groveUtil.WithIntegrationTrailers(git, dir, "Integrate repoA", "repoA", orphanCommit)
+		if err != nil {
+			t.Fatalf("WithIntegrationTrailers failed: %v", err)
+		}
+		testutil.Git(t, dir, "checkout", "-q", "main")
+		testutil.Git(t, dir, "commit", "-q", "--allow-empty", "-m", message)
+		testutil.Git(t, dir, "checkout", "-q", orphan)
+	}
+
+	// Never integrated: the trunk's subtree split, which the orphan branch was created from
+	if got := base(); got != root {
+		t.Errorf("expected the subtree split %s as base, got %s", root, got)
+	}
+	if got := pending(); len(got) != 0 {
+		t.Errorf("expected nothing pending after registration, got %v", got)
+	}
+
+	testutil.Git(t, dir, "checkout", "-q", orphan)
+	first := orphanCommit("a.txt", "a2")
+	second := orphanCommit("a.txt", "a3")
+	if got := pending(); !reflect.DeepEqual(got, []string{first, second}) {
+		t.Errorf("expected both orphan commits pending, got %v", got)
+	}
+
+	// The GG-Orphan-Commit trailer on the trunk moves the base
+	recordIntegration(first)
+	if got := base(); got != first {
+		t.Errorf("expected the recorded integration %s as base, got %s", first, got)
+	}
+	if got := pending(); !reflect.DeepEqual(got, []string{second}) {
+		t.Errorf("expected only the second commit pending, got %v", got)
+	}
+	if last, err := groveUtil.LastIntegration(git, dir, "main", "repoA"); err != nil || last == nil || last.OrphanCommit != first {
+		t.Errorf("unexpected last integration: %+v (%v)", last, err)
+	}
+
+	// Records that are no longer on the orphan branch (reset or rewritten) are ignored
+	testutil.Git(t, dir, "checkout", "-q", "-b", "abandoned")
+	abandoned := orphanCommit("a.txt", "abandoned")
+	testutil.Git(t, dir, "checkout", "-q", orphan)
+	recordIntegration(abandoned)
+	if got := base(); got != first {
+		t.Errorf("expected the newest ancestor record %s as base, got %s", first, got)
+	}
+
+	// Merges made on the orphan branch stay in the pending range, after their parents
+	testutil.Git(t, dir, "checkout", "-q", "-b", "feature")
+	feature := orphanCommit("feature.txt", "f")
+	testutil.Git(t, dir, "checkout", "-q", orphan)
+	mainline := orphanCommit("a.txt", "a4")
+	testutil.Git(t, dir, "merge", "-q", "--no-ff", "-m", "Merge feature", "feature")
+	merge := testutil.Git(t, dir, "rev-parse", "HEAD")
+
+	got := pending()
+	if len(got) != 4 || got[0] != second || got[3] != merge {
+		t.Fatalf("expected second, feature, mainline and the merge pending, got %v", got)
+	}
+	if !(contains(got, feature) && contains(got, mainline)) {
+		t.Errorf("expected both sides of the merge pending, got %v", got)
+	}
+}
+
+func TestTrunkOnlyCommits(t *testing.T) {
+	git := gitUtil.NewExecClient()
+	dir := testutil.TwoRepoWorkspace(t)
+	orphan := "gg/main/repoA"
+
+	trunkOnly := func() []string {
+		t.Helper()
+		commits, err := groveUtil.TrunkOnlyCommits(git, dir, "main", orphan, "services/repoA")
+		if err != nil {
+			t.Fatalf("TrunkOnlyCommits failed: %v", err)
+		}
+		return commits
+	}
+
+	if got := trunkOnly(); len(got) != 0 {
+		t.Errorf("expected nothing trunk-only after registration, got %v", got)
+	}
+
+	// A direct trunk edit to the repo (the split rewrites it, so compare messages)
+	testutil.WriteFile(t, dir, "services/repoA/a.txt", "trunk edit")
+	testutil.Git(t, dir, "commit", "-q", "-am", "Edit repoA on the trunk")
+	// Edits elsewhere are not the repo's business
+	testutil.WriteFile(t, dir, "services/repoB/b.txt", "b2")
+	testutil.Git(t, dir, "commit", "-q", "-am", "Edit repoB on the trunk")
+
+	got := trunkOnly()
+	if len(got) != 1 || testutil.Git(t, dir, "log", "-1", "--format=%s", got[0]) != "Edit repoA on the trunk" {
+		t.Errorf("expected the repoA trunk edit, got %v", got)
+	}
+}
+
+func contains(list []string, item string) bool {
+	for _, entry := range list {
+		if entry == item {
+			return true
+		}
+	}
+	return false
+}
@@ -1,6 +1,36 @@
 package model
 
 type GGRepo struct {
-	Name string
-	Path string
+	Name         string
+	Path         string
+	Tags         []string     `json:"Tags,omitempty"`         // Labels applied to pull requests opened for this repo
+	Checks       []string     `json:"Checks,omitempty"`       // Commands run in the repo directory before integration (e.g. "go test ./...")
+	CommitRules  *CommitRules `json:"CommitRules,omitempty"`  // Commit message conventions enforced by the commit-msg hook
+	NeutralPaths []string     `json:"NeutralPaths,omitempty"` // Root paths/globs that may be committed together with this repo (e.g. "CODEOWNERS")
+	ProtectTrunk *bool        `json:"ProtectTrunk,omitempty"` // Overrides the global protect_trunk setting for this repo
+}
+
+// CommitRules describes the commit message conventions of a repository.
+// The subject is checked after the "[repo] " prefix has been removed.
+type CommitRules struct {
+	Conventional     bool     `json:"conventional,omitempty"`       // Require "type(scope): description"
+	Types            []string `json:"types,omitempty"`              // Allowed Conventional Commits types (defaults to the standard set)
+	Scopes           []string `json:"scopes,omitempty"`             // Allowed scopes; empty means any scope (or none)
+	RequireScope     bool     `json:"require_scope,omitempty"`      // Reject Conventional Commits without a scope
+	TicketPattern    string   `json:"ticket_pattern,omitempty"`     // Regular expression that must match somewhere in the message (e.g. "[A-Z]+-[0-9]+")
+	MaxSubjectLength int      `json:"max_subject_length,omitempty"` // Maximum subject length in characters; 0 disables the check
+}
+
+// Shared path policies
+const (
+	SharedPolicyStandalone = "standalone" // May be committed alone (or with root files), never together with a repository
+	SharedPolicyWithRepo   = "with-repo"  // May be committed alone or together with any one repository
+	SharedPolicyExclusive  = "exclusive"  // Must be committed on its own, without any other files
+)
+
+// SharedPath is a cross-cutting directory (e.g. "proto/") that belongs to no single repository.
+type SharedPath struct {
+	Name   string
+	Path   string // Root-relative path or glob
+	Policy string // One of the SharedPolicy* values; empty means standalone
 }